@@ -0,0 +1,55 @@
+// Command collabupdate brings artist_collab and artist_collab_summary up
+// to date after the MusicBrainz mirror replicates, re-deriving only the
+// recordings that changed since the last run, and bumps the data version
+// caches key on. Run it after each replication packet, or let it loop:
+//
+//	PG_DSN=... go run ./cmd/collabupdate -every 1h
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/search"
+)
+
+func main() {
+	every := flag.Duration("every", 0, "repeat at this interval (0 = run once)")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	s, err := search.Open("")
+	if err != nil {
+		log.Fatalf("open db: %v", err)
+	}
+	defer s.Close()
+
+	for {
+		if err := update(ctx, s); err != nil {
+			if *every == 0 {
+				log.Fatal(err)
+			}
+			log.Print(err)
+		}
+		if *every == 0 {
+			return
+		}
+		time.Sleep(*every)
+	}
+}
+
+func update(ctx context.Context, s *search.Store) error {
+	start := time.Now()
+	up, err := s.UpdateCollab(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("[collabupdate] %s..%s: %d recordings, %d pairs, data version %d (replication %d) in %s",
+		up.Since.Format(time.RFC3339), up.Through.Format(time.RFC3339),
+		up.Recordings, up.Pairs, up.DataVersion, up.ReplicationSequence,
+		time.Since(start).Round(time.Second))
+	return nil
+}
@@ -0,0 +1,71 @@
+// Command communities runs label-propagation community detection over
+// artist_collab_summary and stores a community per artist, plus a summary
+// (size, best-connected artists, dominant tags) per community.
+//
+// Run it after Store.Migrate has built the summary table:
+//
+//	PG_DSN=... go run ./cmd/communities -iterations 30
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/analytics"
+	"github.com/Jonnymurillo288/MelodyMap/internal/search"
+)
+
+func main() {
+	iterations := flag.Int("iterations", 30, "maximum label-propagation passes")
+	seed := flag.Int64("seed", 1, "random seed for the node visiting order")
+	topN := flag.Int("top", 10, "top artists by degree stored per community")
+	minSize := flag.Int("min-size", 2, "communities smaller than this get no summary row")
+	flag.Parse()
+
+	ctx := context.Background()
+	start := time.Now()
+
+	s, err := search.Open("")
+	if err != nil {
+		log.Fatalf("open db: %v", err)
+	}
+	defer s.Close()
+
+	if err := s.MigrateAnalytics(ctx); err != nil {
+		log.Fatalf("migrate: %v", err)
+	}
+
+	g, err := s.LoadCollabGraph(ctx, func(edges int) {
+		log.Printf("[communities] loaded %d edges", edges)
+	})
+	if err != nil {
+		log.Fatalf("load graph: %v", err)
+	}
+	log.Printf("[communities] graph: %d artists, %d edges", g.NumNodes(), g.NumEdges())
+
+	labels := analytics.LabelPropagation(g, *iterations, *seed, func(iter, changed int) {
+		log.Printf("[communities] pass %d: %d labels changed", iter, changed)
+	})
+	communities := analytics.Communities(g, labels, *topN)
+
+	rows := make([]search.CommunityRow, 0, len(communities))
+	for _, c := range communities {
+		if len(c.Members) < *minSize {
+			continue
+		}
+		top := make([]int32, len(c.Top))
+		for i, v := range c.Top {
+			top[i] = g.IDs[v]
+		}
+		rows = append(rows, search.CommunityRow{ID: c.ID, Size: len(c.Members), TopArtistIDs: top})
+	}
+	log.Printf("[communities] %d communities, %d with >= %d members", len(communities), len(rows), *minSize)
+
+	if err := s.SaveCommunities(ctx, g.IDs, labels, rows); err != nil {
+		log.Fatalf("save: %v", err)
+	}
+
+	log.Printf("[communities] done in %s", time.Since(start).Round(time.Second))
+}
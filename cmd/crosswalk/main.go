@@ -0,0 +1,32 @@
+// Command crosswalk rebuilds spotify_crosswalk, the MBID <-> Spotify ID
+// mapping taken from MusicBrainz URL relationships. Store.Migrate also
+// rebuilds it; run this after importing a fresh dump without migrating.
+//
+//	PG_DSN=... go run ./cmd/crosswalk
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/search"
+)
+
+func main() {
+	ctx := context.Background()
+	start := time.Now()
+
+	s, err := search.Open("")
+	if err != nil {
+		log.Fatalf("open db: %v", err)
+	}
+	defer s.Close()
+
+	n, err := s.RebuildSpotifyCrosswalk(ctx)
+	if err != nil {
+		log.Fatalf("rebuild: %v", err)
+	}
+
+	log.Printf("[crosswalk] linked %d entities in %s", n, time.Since(start).Round(time.Second))
+}
@@ -0,0 +1,147 @@
+// Command pairmatrix precomputes shortest-path distances and one witness
+// path between every pair of artists in static/top_artists.txt (or a
+// random sample of sources against the full list). It runs bit-parallel
+// multi-source BFS over artist_collab_summary, 64 sources per pass, and
+// stores the result in artist_pair_distance for the hardest-pairs
+// leaderboard and instant pair answers.
+//
+//	PG_DSN=... go run ./cmd/pairmatrix -list static/top_artists.txt -sample 500
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/analytics"
+	"github.com/Jonnymurillo288/MelodyMap/internal/search"
+)
+
+func main() {
+	list := flag.String("list", "static/top_artists.txt", "artist names, one per line")
+	sample := flag.Int("sample", 0, "number of source artists to sample (0 = all pairs)")
+	seed := flag.Int64("seed", 1, "random seed for sampling")
+	maxDepth := flag.Int("max-depth", 0, "stop BFS after this many hops (0 = unbounded)")
+	flag.Parse()
+
+	ctx := context.Background()
+	start := time.Now()
+
+	names, err := readNames(*list)
+	if err != nil {
+		log.Fatalf("read %s: %v", *list, err)
+	}
+
+	s, err := search.Open("")
+	if err != nil {
+		log.Fatalf("open db: %v", err)
+	}
+	defer s.Close()
+
+	if err := s.MigrateAnalytics(ctx); err != nil {
+		log.Fatalf("migrate: %v", err)
+	}
+	version, err := s.DataVersion(ctx)
+	if err != nil {
+		log.Fatalf("data version: %v", err)
+	}
+
+	top, missing, err := s.ResolveTopArtists(ctx, names)
+	if err != nil {
+		log.Fatalf("resolve artists: %v", err)
+	}
+	log.Printf("[pairmatrix] resolved %d/%d names (%d missing)", len(top), len(names), len(missing))
+
+	g, err := s.LoadCollabGraph(ctx, func(edges int) {
+		log.Printf("[pairmatrix] loaded %d edges", edges)
+	})
+	if err != nil {
+		log.Fatalf("load graph: %v", err)
+	}
+	log.Printf("[pairmatrix] graph: %d artists, %d edges", g.NumNodes(), g.NumEdges())
+
+	// artists without collaborations are not in the graph; every pair
+	// involving them is unreachable
+	var nodes []int32
+	var isolated []search.TopArtist
+	for _, a := range top {
+		if i, ok := g.Index(int(a.ID)); ok {
+			nodes = append(nodes, i)
+		} else {
+			isolated = append(isolated, a)
+		}
+	}
+
+	sources := append([]int32(nil), nodes...)
+	if *sample > 0 && *sample < len(sources) {
+		rng := rand.New(rand.NewSource(*seed))
+		rng.Shuffle(len(sources), func(i, j int) { sources[i], sources[j] = sources[j], sources[i] })
+		sources = sources[:*sample]
+	}
+	sourcePos := make(map[int32]int, len(sources))
+	for i, v := range sources {
+		sourcePos[v] = i
+	}
+
+	err = s.SavePairMatrix(ctx, top, version, func(emit func(search.PairDistance) error) error {
+		for lo := 0; lo < len(sources); lo += analytics.MSBFSWidth {
+			batch := sources[lo:min(lo+analytics.MSBFSWidth, len(sources))]
+			r := analytics.MultiSourceBFS(g, batch, *maxDepth)
+
+			for b, src := range batch {
+				for _, t := range nodes {
+					// both ends sampled: emit each unordered pair once
+					if p, ok := sourcePos[t]; ok && p <= lo+b {
+						continue
+					}
+					pd := search.PairDistance{
+						StartID:  g.IDs[src],
+						TargetID: g.IDs[t],
+						Distance: r.Dist(b, t),
+					}
+					for _, v := range r.Path(g, b, t) {
+						pd.Path = append(pd.Path, g.IDs[v])
+					}
+					if err := emit(pd); err != nil {
+						return err
+					}
+				}
+				for _, a := range isolated {
+					if err := emit(search.PairDistance{StartID: g.IDs[src], TargetID: a.ID, Distance: -1}); err != nil {
+						return err
+					}
+				}
+			}
+			log.Printf("[pairmatrix] %d/%d sources done", min(lo+analytics.MSBFSWidth, len(sources)), len(sources))
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("save: %v", err)
+	}
+
+	log.Printf("[pairmatrix] done in %s", time.Since(start).Round(time.Second))
+}
+
+// readNames reads one artist name per line, ignoring blanks.
+func readNames(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if name := strings.TrimSpace(sc.Text()); name != "" {
+			out = append(out, name)
+		}
+	}
+	return out, sc.Err()
+}
@@ -0,0 +1,74 @@
+// Command similarity rebuilds the MinHash/LSH index behind
+// /api/artists/{mbid}/similar. Each artist's collaborator set is hashed
+// into -bands bucket keys of -rows hashes each; artists sharing a bucket
+// become candidates that the API rescores exactly. Run it after
+// Store.Migrate has refreshed artist_collab_summary.
+//
+//	PG_DSN=... go run ./cmd/similarity -bands 16 -rows 2
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/analytics"
+	"github.com/Jonnymurillo288/MelodyMap/internal/search"
+)
+
+func main() {
+	bands := flag.Int("bands", 16, "LSH bands")
+	rows := flag.Int("rows", 2, "MinHash rows per band")
+	seed := flag.Int64("seed", 1, "MinHash seed")
+	minDegree := flag.Int("min-degree", 1, "skip artists with fewer collaborators")
+	maxBucket := flag.Int("max-bucket", 1000, "drop buckets with more artists than this")
+	flag.Parse()
+
+	ctx := context.Background()
+	start := time.Now()
+
+	s, err := search.Open("")
+	if err != nil {
+		log.Fatalf("open db: %v", err)
+	}
+	defer s.Close()
+
+	if err := s.MigrateAnalytics(ctx); err != nil {
+		log.Fatalf("migrate: %v", err)
+	}
+
+	g, err := s.LoadCollabGraph(ctx, func(edges int) {
+		log.Printf("[similarity] loaded %d edges", edges)
+	})
+	if err != nil {
+		log.Fatalf("load graph: %v", err)
+	}
+	log.Printf("[similarity] graph: %d artists, %d edges", g.NumNodes(), g.NumEdges())
+
+	mh := analytics.NewMinHasher(*bands, *rows, *seed)
+	indexed := 0
+
+	err = s.SaveLSHBuckets(ctx, *maxBucket, func(emit func(int16, int64, int32) error) error {
+		for i := int32(0); i < int32(g.NumNodes()); i++ {
+			if g.Degree(i) < *minDegree {
+				continue
+			}
+			for b, key := range mh.NodeBandKeys(g, i) {
+				if err := emit(int16(b), key, g.IDs[i]); err != nil {
+					return err
+				}
+			}
+			indexed++
+			if indexed%100_000 == 0 {
+				log.Printf("[similarity] hashed %d artists", indexed)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("save: %v", err)
+	}
+
+	log.Printf("[similarity] indexed %d artists in %s", indexed, time.Since(start).Round(time.Second))
+}
@@ -0,0 +1,104 @@
+// Command snapshot writes the collaboration graph around a list of
+// artists to a snapshot file, which the server can search without a
+// database (GRAPH_SOURCE=snapshot GRAPH_SNAPSHOT=<file>).
+//
+//	PG_DSN=... go run ./cmd/snapshot -list static/top_artists.txt -depth 2 -out graph.json.gz
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"flag"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/search"
+)
+
+func main() {
+	list := flag.String("list", "static/top_artists.txt", "artist names, one per line")
+	depth := flag.Int("depth", 2, "hops to expand around the listed artists")
+	limit := flag.Int("limit", 50, "strongest neighbors kept per artist")
+	tracks := flag.Int("tracks", 5, "tracks of evidence kept per edge (0 = none)")
+	out := flag.String("out", "graph.json.gz", "output file; .gz is gzipped")
+	flag.Parse()
+
+	ctx := context.Background()
+	start := time.Now()
+
+	names, err := readNames(*list)
+	if err != nil {
+		log.Fatalf("read %s: %v", *list, err)
+	}
+
+	s, err := search.Open("")
+	if err != nil {
+		log.Fatalf("open db: %v", err)
+	}
+	defer s.Close()
+
+	top, missing, err := s.ResolveTopArtists(ctx, names)
+	if err != nil {
+		log.Fatalf("resolve artists: %v", err)
+	}
+	log.Printf("[snapshot] resolved %d/%d names (%d missing)", len(top), len(names), len(missing))
+
+	seeds := make([]search.ArtistRef, 0, len(top))
+	for _, a := range top {
+		seeds = append(seeds, search.ArtistRef{MBID: a.MBID, Name: a.Name})
+	}
+
+	g, err := search.SnapshotNeighborhood(ctx, s, seeds, *depth, *limit, *tracks)
+	if err != nil {
+		log.Fatalf("load neighborhood: %v", err)
+	}
+
+	if err := writeSnapshot(*out, g); err != nil {
+		log.Fatalf("write %s: %v", *out, err)
+	}
+	log.Printf("[snapshot] wrote %d artists to %s in %s", g.Len(), *out, time.Since(start).Round(time.Second))
+}
+
+func writeSnapshot(path string, g *search.MemoryGraph) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if !strings.HasSuffix(path, ".gz") {
+		if err := g.WriteSnapshot(f); err != nil {
+			return err
+		}
+		return f.Close()
+	}
+
+	gz := gzip.NewWriter(f)
+	if err := g.WriteSnapshot(gz); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func readNames(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var names []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if name := strings.TrimSpace(sc.Text()); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, sc.Err()
+}
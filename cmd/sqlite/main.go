@@ -0,0 +1,101 @@
+// Command sqlite extracts the collaboration graph around a list of
+// artists from Postgres into a single SQLite file, so searches can run on
+// a laptop without a MusicBrainz mirror:
+//
+//	PG_DSN=... go run ./cmd/sqlite -list static/top_artists.txt -depth 2 -out melodymap.db
+//	GRAPH_SOURCE=sqlite GRAPH_SQLITE=melodymap.db go run ./main
+//
+// With -fixture it writes the small built-in test graph instead and needs
+// no database.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/search"
+)
+
+func main() {
+	list := flag.String("list", "static/top_artists.txt", "artist names, one per line")
+	depth := flag.Int("depth", 2, "hops to extract around the listed artists")
+	limit := flag.Int("limit", 50, "strongest neighbors kept per artist")
+	tracks := flag.Int("tracks", 3, "tracks of evidence kept per edge (0 = none)")
+	out := flag.String("out", "melodymap.db", "SQLite file to write")
+	fixture := flag.Bool("fixture", false, "write the built-in fixture graph instead of reading Postgres")
+	flag.Parse()
+
+	ctx := context.Background()
+	start := time.Now()
+
+	var g *search.MemoryGraph
+	if *fixture {
+		g = search.FixtureGraph()
+	} else {
+		var err error
+		g, err = extract(ctx, *list, *depth, *limit, *tracks)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	db, err := search.OpenSQLite(*out)
+	if err != nil {
+		log.Fatalf("open %s: %v", *out, err)
+	}
+	defer db.Close()
+
+	if err := db.ImportGraph(ctx, g); err != nil {
+		log.Fatalf("import: %v", err)
+	}
+	log.Printf("[sqlite] wrote %d artists to %s in %s", g.Len(), *out, time.Since(start).Round(time.Second))
+}
+
+// extract loads the neighborhood of the listed artists from Postgres.
+func extract(ctx context.Context, list string, depth, limit, tracks int) (*search.MemoryGraph, error) {
+	names, err := readNames(list)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", list, err)
+	}
+
+	s, err := search.Open("")
+	if err != nil {
+		return nil, fmt.Errorf("open db: %w", err)
+	}
+	defer s.Close()
+
+	top, missing, err := s.ResolveTopArtists(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("resolve artists: %w", err)
+	}
+	log.Printf("[sqlite] resolved %d/%d names (%d missing)", len(top), len(names), len(missing))
+
+	seeds := make([]search.ArtistRef, 0, len(top))
+	for _, a := range top {
+		seeds = append(seeds, search.ArtistRef{MBID: a.MBID, Name: a.Name})
+	}
+	return search.SnapshotNeighborhood(ctx, s, seeds, depth, limit, tracks)
+}
+
+func readNames(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var names []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if name := strings.TrimSpace(sc.Text()); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, sc.Err()
+}
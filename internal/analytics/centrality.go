@@ -0,0 +1,162 @@
+package analytics
+
+import (
+	"math"
+	"math/rand"
+)
+
+// PageRank computes weighted PageRank: a walker leaves an artist along
+// each collaboration in proportion to its shared recordings. Isolated
+// artists redistribute their rank uniformly. It stops after maxIter passes
+// or once the L1 change drops below tol. Scores sum to 1.
+//
+// Memory is three float64 slices of NumNodes, independent of edge count.
+func PageRank(g *Graph, damping float64, maxIter int, tol float64, progress func(iter int, delta float64)) []float64 {
+	n := g.NumNodes()
+	if n == 0 {
+		return nil
+	}
+
+	outW := make([]float64, n)
+	for v := int32(0); v < int32(n); v++ {
+		_, w := g.Neighbors(v)
+		for _, x := range w {
+			outW[v] += float64(x)
+		}
+	}
+
+	rank := make([]float64, n)
+	next := make([]float64, n)
+	for i := range rank {
+		rank[i] = 1 / float64(n)
+	}
+
+	for iter := 1; iter <= maxIter; iter++ {
+		dangling := 0.0
+		for v := range rank {
+			if outW[v] == 0 {
+				dangling += rank[v]
+			}
+		}
+		base := (1-damping)/float64(n) + damping*dangling/float64(n)
+		for i := range next {
+			next[i] = base
+		}
+
+		// the graph is symmetric, so pushing along out-edges is the same
+		// as pulling along in-edges
+		for v := int32(0); v < int32(n); v++ {
+			if outW[v] == 0 {
+				continue
+			}
+			share := damping * rank[v] / outW[v]
+			adj, w := g.Neighbors(v)
+			for k, u := range adj {
+				next[u] += share * float64(w[k])
+			}
+		}
+
+		delta := 0.0
+		for i := range rank {
+			delta += math.Abs(next[i] - rank[i])
+		}
+		rank, next = next, rank
+
+		if progress != nil {
+			progress(iter, delta)
+		}
+		if delta < tol {
+			break
+		}
+	}
+
+	return rank
+}
+
+// ApproxBetweenness estimates unweighted betweenness centrality with
+// Brandes' algorithm from `samples` random source artists, scaled up to
+// the full graph. With samples >= NumNodes it is exact.
+//
+// Each source is one BFS using O(NumNodes) scratch space that is reused
+// across sources; predecessors are recovered from BFS distances instead of
+// being stored, which keeps memory bounded on hub-heavy graphs.
+func ApproxBetweenness(g *Graph, samples int, seed int64, progress func(done, total int)) []float64 {
+	n := g.NumNodes()
+	bc := make([]float64, n)
+	if n == 0 || samples <= 0 {
+		return bc
+	}
+
+	sources := make([]int32, n)
+	for i := range sources {
+		sources[i] = int32(i)
+	}
+	if samples < n {
+		rng := rand.New(rand.NewSource(seed))
+		rng.Shuffle(n, func(i, j int) { sources[i], sources[j] = sources[j], sources[i] })
+		sources = sources[:samples]
+	}
+
+	dist := make([]int32, n)
+	sigma := make([]float64, n)
+	delta := make([]float64, n)
+	order := make([]int32, 0, n)
+
+	for i := range dist {
+		dist[i] = -1
+	}
+
+	for done, s := range sources {
+		order = order[:0]
+		dist[s] = 0
+		sigma[s] = 1
+		order = append(order, s)
+
+		// forward BFS; order doubles as the queue
+		for head := 0; head < len(order); head++ {
+			v := order[head]
+			adj, _ := g.Neighbors(v)
+			for _, w := range adj {
+				if dist[w] < 0 {
+					dist[w] = dist[v] + 1
+					order = append(order, w)
+				}
+				if dist[w] == dist[v]+1 {
+					sigma[w] += sigma[v]
+				}
+			}
+		}
+
+		// backward accumulation in reverse BFS order
+		for k := len(order) - 1; k >= 0; k-- {
+			w := order[k]
+			adj, _ := g.Neighbors(w)
+			for _, v := range adj {
+				if dist[v] == dist[w]-1 {
+					delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+				}
+			}
+			if w != s {
+				bc[w] += delta[w]
+			}
+		}
+
+		// reset only what this BFS touched
+		for _, v := range order {
+			dist[v] = -1
+			sigma[v] = 0
+			delta[v] = 0
+		}
+
+		if progress != nil {
+			progress(done+1, len(sources))
+		}
+	}
+
+	// undirected graph: every pair was counted from both ends
+	scale := float64(n) / float64(len(sources)) / 2
+	for i := range bc {
+		bc[i] *= scale
+	}
+	return bc
+}
@@ -0,0 +1,62 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPageRank_StarCenterRanksHighest(t *testing.T) {
+	g := buildGraph(t, [][3]int{{0, 1, 1}, {0, 2, 1}, {0, 3, 1}, {0, 4, 1}})
+	pr := PageRank(g, 0.85, 100, 1e-10, nil)
+
+	sum := 0.0
+	for _, v := range pr {
+		sum += v
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Fatalf("expected scores to sum to 1, got %v", sum)
+	}
+
+	center, _ := g.Index(0)
+	for i, v := range pr {
+		if int32(i) != center && v >= pr[center] {
+			t.Fatalf("leaf %d outranks center: %v >= %v", g.IDs[i], v, pr[center])
+		}
+	}
+}
+
+func TestPageRank_FollowsSharedWeights(t *testing.T) {
+	// 0 collaborates heavily with 1 and once with 2
+	g := buildGraph(t, [][3]int{{0, 1, 20}, {0, 2, 1}})
+	pr := PageRank(g, 0.85, 100, 1e-10, nil)
+
+	i1, _ := g.Index(1)
+	i2, _ := g.Index(2)
+	if pr[i1] <= pr[i2] {
+		t.Fatalf("expected heavy collaborator to rank higher: %v <= %v", pr[i1], pr[i2])
+	}
+}
+
+func TestApproxBetweenness_ExactOnPath(t *testing.T) {
+	// 0 - 1 - 2 - 3: middle nodes carry 2 shortest paths each
+	g := buildGraph(t, [][3]int{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}})
+	bc := ApproxBetweenness(g, 100, 1, nil)
+
+	want := map[int]float64{0: 0, 1: 2, 2: 2, 3: 0}
+	for id, w := range want {
+		i, _ := g.Index(id)
+		if math.Abs(bc[i]-w) > 1e-9 {
+			t.Fatalf("betweenness of %d: want %v, got %v", id, w, bc[i])
+		}
+	}
+}
+
+func TestApproxBetweenness_SampledStaysFinite(t *testing.T) {
+	g := buildGraph(t, [][3]int{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {4, 0, 1}})
+	bc := ApproxBetweenness(g, 2, 7, nil)
+	for i, v := range bc {
+		if math.IsNaN(v) || math.IsInf(v, 0) || v < 0 {
+			t.Fatalf("bad betweenness for node %d: %v", i, v)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package analytics
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Community is one detected scene: its members and best-connected artists.
+type Community struct {
+	ID      int32
+	Members []int32 // node indices
+	Top     []int32 // node indices of the highest-degree members
+}
+
+// LabelPropagation assigns every node a community label using weighted
+// asynchronous label propagation: each node repeatedly adopts the label
+// carrying the most shared recordings among its neighbors, until no label
+// changes or maxIter passes have run. Ties keep the current label, then
+// fall back to the smallest label, so a fixed seed gives a fixed result.
+//
+// Labels are returned compacted to 0..K-1.
+func LabelPropagation(g *Graph, maxIter int, seed int64, progress func(iter, changed int)) []int32 {
+	n := g.NumNodes()
+	labels := make([]int32, n)
+	order := make([]int32, n)
+	for i := range labels {
+		labels[i] = int32(i)
+		order[i] = int32(i)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	tally := make(map[int32]int64)
+
+	for iter := 1; iter <= maxIter; iter++ {
+		rng.Shuffle(n, func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+		changed := 0
+		for _, v := range order {
+			adj, w := g.Neighbors(v)
+			if len(adj) == 0 {
+				continue
+			}
+
+			clear(tally)
+			for k, u := range adj {
+				tally[labels[u]] += int64(w[k])
+			}
+
+			cur := labels[v]
+			best, bestW := cur, tally[cur]
+			for l, lw := range tally {
+				if lw > bestW || (lw == bestW && best != cur && l < best) {
+					best, bestW = l, lw
+				}
+			}
+
+			if best != cur {
+				labels[v] = best
+				changed++
+			}
+		}
+
+		if progress != nil {
+			progress(iter, changed)
+		}
+		if changed == 0 {
+			break
+		}
+	}
+
+	return compactLabels(labels)
+}
+
+func compactLabels(labels []int32) []int32 {
+	remap := make(map[int32]int32)
+	for i, l := range labels {
+		id, ok := remap[l]
+		if !ok {
+			id = int32(len(remap))
+			remap[l] = id
+		}
+		labels[i] = id
+	}
+	return labels
+}
+
+// Communities groups nodes by label, largest community first, keeping the
+// topN highest-degree members of each.
+func Communities(g *Graph, labels []int32, topN int) []Community {
+	var k int32
+	for _, l := range labels {
+		k = max(k, l+1)
+	}
+
+	out := make([]Community, k)
+	for i := range out {
+		out[i].ID = int32(i)
+	}
+	for v, l := range labels {
+		out[l].Members = append(out[l].Members, int32(v))
+	}
+	for i := range out {
+		out[i].Top = g.TopByDegree(out[i].Members, topN)
+	}
+
+	sortCommunities(out)
+	return out
+}
+
+func sortCommunities(cs []Community) {
+	// labels come out in discovery order; present the biggest scenes first
+	sort.SliceStable(cs, func(i, j int) bool {
+		return len(cs[i].Members) > len(cs[j].Members)
+	})
+}
@@ -0,0 +1,100 @@
+package analytics
+
+import "testing"
+
+// buildGraph builds an undirected test graph from {a, b, weight} triples.
+func buildGraph(t *testing.T, edges [][3]int) *Graph {
+	t.Helper()
+
+	adj := make(map[int][][2]int)
+	maxID := 0
+	for _, e := range edges {
+		adj[e[0]] = append(adj[e[0]], [2]int{e[1], e[2]})
+		adj[e[1]] = append(adj[e[1]], [2]int{e[0], e[2]})
+		maxID = max(maxID, e[0], e[1])
+	}
+
+	b := NewBuilder()
+	for id := 0; id <= maxID; id++ {
+		for _, nb := range adj[id] {
+			if err := b.Add(id, nb[0], nb[1]); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return b.Build()
+}
+
+func TestBuilder_RejectsOutOfOrder(t *testing.T) {
+	b := NewBuilder()
+	if err := b.Add(5, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Add(3, 1, 1); err == nil {
+		t.Fatalf("expected out-of-order error")
+	}
+}
+
+func TestBuilder_DropsUnknownNeighbors(t *testing.T) {
+	b := NewBuilder()
+	_ = b.Add(1, 2, 3)
+	_ = b.Add(1, 99, 1) // 99 never appears as a source
+	_ = b.Add(2, 1, 3)
+	g := b.Build()
+
+	if g.NumNodes() != 2 || g.NumEdges() != 2 {
+		t.Fatalf("expected 2 nodes / 2 edges, got %d / %d", g.NumNodes(), g.NumEdges())
+	}
+	i, _ := g.Index(1)
+	adj, w := g.Neighbors(i)
+	if len(adj) != 1 || g.IDs[adj[0]] != 2 || w[0] != 3 {
+		t.Fatalf("unexpected adjacency for artist 1: %v %v", adj, w)
+	}
+}
+
+func TestLabelPropagation_SplitsTwoCliques(t *testing.T) {
+	// two tight 4-cliques joined by a single one-off credit (3-4)
+	var edges [][3]int
+	for _, clique := range [][]int{{0, 1, 2, 3}, {4, 5, 6, 7}} {
+		for i := 0; i < len(clique); i++ {
+			for j := i + 1; j < len(clique); j++ {
+				edges = append(edges, [3]int{clique[i], clique[j], 10})
+			}
+		}
+	}
+	edges = append(edges, [3]int{3, 4, 1})
+
+	g := buildGraph(t, edges)
+	labels := LabelPropagation(g, 20, 1, nil)
+
+	idx := func(id int) int32 { i, _ := g.Index(id); return i }
+	for _, id := range []int{1, 2, 3} {
+		if labels[idx(id)] != labels[idx(0)] {
+			t.Fatalf("artist %d split from its clique", id)
+		}
+	}
+	for _, id := range []int{5, 6, 7} {
+		if labels[idx(id)] != labels[idx(4)] {
+			t.Fatalf("artist %d split from its clique", id)
+		}
+	}
+	if labels[idx(0)] == labels[idx(4)] {
+		t.Fatalf("expected the weak bridge to separate the cliques")
+	}
+
+	cs := Communities(g, labels, 2)
+	if len(cs) != 2 || len(cs[0].Members) != 4 || len(cs[0].Top) != 2 {
+		t.Fatalf("unexpected communities: %+v", cs)
+	}
+}
+
+func TestLabelPropagation_Deterministic(t *testing.T) {
+	g := buildGraph(t, [][3]int{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}, {2, 3, 1}, {3, 4, 1}})
+	a := LabelPropagation(g, 10, 42, nil)
+	b := LabelPropagation(g, 10, 42, nil)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("same seed gave different labels: %v vs %v", a, b)
+		}
+	}
+}
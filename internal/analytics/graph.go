@@ -0,0 +1,136 @@
+// Package analytics holds the offline graph algorithms that run over the
+// whole collaboration graph: community detection, centrality, similarity.
+//
+// Everything here is pure computation over an in-memory Graph; loading
+// from and persisting to Postgres lives on search.Store, and the cmd/
+// programs wire the two together.
+package analytics
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Graph is a compact, read-only collaboration graph in CSR form. Node
+// indices are dense 0..N-1 and IDs maps them back to internal artist ids.
+// At roughly 8 bytes per directed edge it holds the full MusicBrainz
+// collaboration graph in a few hundred MB.
+type Graph struct {
+	IDs     []int32 // node index -> artist id
+	Offsets []int64 // node i's edges are Adj[Offsets[i]:Offsets[i+1]]
+	Adj     []int32 // neighbor node indices
+	Weights []int32 // shared recordings, parallel to Adj
+
+	index map[int32]int32
+}
+
+// NumNodes returns the number of artists in the graph.
+func (g *Graph) NumNodes() int {
+	return len(g.IDs)
+}
+
+// NumEdges returns the number of directed edges.
+func (g *Graph) NumEdges() int {
+	return len(g.Adj)
+}
+
+// Neighbors returns node i's neighbor indices and edge weights.
+func (g *Graph) Neighbors(i int32) ([]int32, []int32) {
+	lo, hi := g.Offsets[i], g.Offsets[i+1]
+	return g.Adj[lo:hi], g.Weights[lo:hi]
+}
+
+// Degree returns the number of distinct collaborators of node i.
+func (g *Graph) Degree(i int32) int {
+	return int(g.Offsets[i+1] - g.Offsets[i])
+}
+
+// Index maps an artist id to its node index.
+func (g *Graph) Index(artistID int) (int32, bool) {
+	i, ok := g.index[int32(artistID)]
+	return i, ok
+}
+
+// Builder accumulates edges streamed in ascending artist_id order, which
+// is how artist_collab_summary is read (by primary key).
+type Builder struct {
+	ids     []int32
+	offsets []int64
+	raw     []int32 // neighbor artist ids until Build remaps them
+	weights []int32
+}
+
+func NewBuilder() *Builder {
+	return &Builder{offsets: []int64{0}}
+}
+
+// Add records the edge artistID -> neighborID. Calls must be grouped by
+// artistID in ascending order.
+func (b *Builder) Add(artistID, neighborID, shared int) error {
+	a := int32(artistID)
+	if n := len(b.ids); n == 0 || b.ids[n-1] != a {
+		if n > 0 && a < b.ids[n-1] {
+			return fmt.Errorf("edges out of order: artist %d after %d", a, b.ids[n-1])
+		}
+		if n > 0 {
+			b.offsets = append(b.offsets, int64(len(b.raw)))
+		}
+		b.ids = append(b.ids, a)
+	}
+	b.raw = append(b.raw, int32(neighborID))
+	b.weights = append(b.weights, int32(shared))
+	return nil
+}
+
+// Build finalizes the graph. Neighbors that never appeared as a source
+// artist are dropped, so every edge points at a valid node.
+func (b *Builder) Build() *Graph {
+	if len(b.ids) > 0 {
+		b.offsets = append(b.offsets, int64(len(b.raw)))
+	}
+
+	g := &Graph{
+		IDs:   b.ids,
+		index: make(map[int32]int32, len(b.ids)),
+	}
+	for i, id := range b.ids {
+		g.index[id] = int32(i)
+	}
+
+	// remap in place, compacting out unknown neighbors
+	g.Offsets = make([]int64, len(b.ids)+1)
+	w := int64(0)
+	for i := range b.ids {
+		g.Offsets[i] = w
+		for e := b.offsets[i]; e < b.offsets[i+1]; e++ {
+			idx, ok := g.index[b.raw[e]]
+			if !ok {
+				continue
+			}
+			b.raw[w] = idx
+			b.weights[w] = b.weights[e]
+			w++
+		}
+	}
+	g.Offsets[len(b.ids)] = w
+	g.Adj = b.raw[:w]
+	g.Weights = b.weights[:w]
+
+	return g
+}
+
+// TopByDegree returns up to n node indices from nodes, highest degree first.
+func (g *Graph) TopByDegree(nodes []int32, n int) []int32 {
+	out := append([]int32(nil), nodes...)
+	sort.Slice(out, func(i, j int) bool {
+		di, dj := g.Degree(out[i]), g.Degree(out[j])
+		if di != dj {
+			return di > dj
+		}
+		return out[i] < out[j]
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
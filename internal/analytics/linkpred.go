@@ -0,0 +1,91 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+)
+
+// LinkPrediction scores one artist the source has never collaborated with.
+type LinkPrediction struct {
+	ID              int     // candidate artist id
+	CommonNeighbors []int   // shared collaborators justifying the score
+	AdamicAdar      float64 // sum of 1/log(degree) over common neighbors
+	Jaccard         float64 // |common| / |N(source) ∪ N(candidate)|
+}
+
+// PredictLinks ranks the 2-hop neighborhood of source by Adamic-Adar,
+// breaking ties by common-neighbor count and then Jaccard.
+//
+// neighbors holds N(source); neighborsOf[n] holds (a possibly truncated)
+// N(n) for each n in it, which is all the candidate generation needs.
+// degree must hold the true degree of source, of every common neighbor
+// and of every candidate; missing candidates fall back to their
+// common-neighbor count, which only inflates Jaccard.
+func PredictLinks(
+	source int,
+	neighbors []int,
+	neighborsOf map[int][]int,
+	degree map[int]int,
+	limit int,
+) []LinkPrediction {
+
+	adjacent := make(map[int]bool, len(neighbors)+1)
+	adjacent[source] = true
+	for _, n := range neighbors {
+		adjacent[n] = true
+	}
+
+	byID := make(map[int]*LinkPrediction)
+	for _, n := range neighbors {
+		weight := 0.0
+		if d := degree[n]; d > 1 {
+			weight = 1 / math.Log(float64(d))
+		}
+		for _, c := range neighborsOf[n] {
+			if adjacent[c] {
+				continue
+			}
+			p, ok := byID[c]
+			if !ok {
+				p = &LinkPrediction{ID: c}
+				byID[c] = p
+			}
+			p.CommonNeighbors = append(p.CommonNeighbors, n)
+			p.AdamicAdar += weight
+		}
+	}
+
+	srcDeg := degree[source]
+	if srcDeg == 0 {
+		srcDeg = len(neighbors)
+	}
+
+	out := make([]LinkPrediction, 0, len(byID))
+	for _, p := range byID {
+		common := len(p.CommonNeighbors)
+		cDeg := max(degree[p.ID], common)
+		if union := srcDeg + cDeg - common; union > 0 {
+			p.Jaccard = float64(common) / float64(union)
+		}
+		out = append(out, *p)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.AdamicAdar != b.AdamicAdar {
+			return a.AdamicAdar > b.AdamicAdar
+		}
+		if len(a.CommonNeighbors) != len(b.CommonNeighbors) {
+			return len(a.CommonNeighbors) > len(b.CommonNeighbors)
+		}
+		if a.Jaccard != b.Jaccard {
+			return a.Jaccard > b.Jaccard
+		}
+		return a.ID < b.ID
+	})
+
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
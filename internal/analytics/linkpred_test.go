@@ -0,0 +1,54 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPredictLinks_RanksByAdamicAdar(t *testing.T) {
+	// source 1 works with 2, 3 and 4.
+	// 10 also works with 2 and 3; 11 only with hub 4; 3 is already adjacent.
+	neighbors := []int{2, 3, 4}
+	neighborsOf := map[int][]int{
+		2: {1, 10},
+		3: {1, 10, 2},
+		4: {1, 11, 12, 13, 14},
+	}
+	degree := map[int]int{1: 3, 2: 2, 3: 3, 4: 5, 10: 2, 11: 1}
+
+	got := PredictLinks(1, neighbors, neighborsOf, degree, 10)
+
+	if len(got) != 5 {
+		t.Fatalf("expected 5 non-adjacent candidates, got %d: %+v", len(got), got)
+	}
+	if got[0].ID != 10 {
+		t.Fatalf("expected 10 first, got %+v", got[0])
+	}
+	if len(got[0].CommonNeighbors) != 2 {
+		t.Fatalf("expected 2 shared neighbors for 10, got %v", got[0].CommonNeighbors)
+	}
+	wantAA := 1/math.Log(2) + 1/math.Log(3)
+	if math.Abs(got[0].AdamicAdar-wantAA) > 1e-9 {
+		t.Fatalf("adamic-adar: want %v, got %v", wantAA, got[0].AdamicAdar)
+	}
+	// |{2,3}| / |{2,3,4} ∪ {2,3}| = 2/3
+	if math.Abs(got[0].Jaccard-2.0/3.0) > 1e-9 {
+		t.Fatalf("jaccard: want 2/3, got %v", got[0].Jaccard)
+	}
+
+	for _, p := range got {
+		if p.ID == 1 || p.ID == 2 || p.ID == 3 || p.ID == 4 {
+			t.Fatalf("adjacent artist %d should not be predicted", p.ID)
+		}
+	}
+}
+
+func TestPredictLinks_Limit(t *testing.T) {
+	got := PredictLinks(1, []int{2}, map[int][]int{2: {3, 4, 5}}, map[int]int{2: 4}, 2)
+	if len(got) != 2 {
+		t.Fatalf("expected limit of 2, got %d", len(got))
+	}
+	if got[0].ID != 3 || got[1].ID != 4 {
+		t.Fatalf("expected id tie-break, got %d, %d", got[0].ID, got[1].ID)
+	}
+}
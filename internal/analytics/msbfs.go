@@ -0,0 +1,114 @@
+package analytics
+
+import "math/bits"
+
+// MSBFSWidth is how many sources one MultiSourceBFS pass can carry: one
+// bit per source in a uint64.
+const MSBFSWidth = 64
+
+// unreached marks a (node, source) pair the traversal never visited.
+const unreached = 0xFF
+
+// MSBFS holds the hop distances from up to MSBFSWidth sources to every
+// node, one byte per (node, source). For the full collaboration graph
+// that is 64 bytes per artist, reused across batches by the caller.
+type MSBFS struct {
+	Sources []int32
+	level   []uint8
+}
+
+// MultiSourceBFS runs unweighted BFS from every node in sources at once,
+// sharing each edge scan between all of them (bit-parallel MS-BFS).
+// maxDepth <= 0 means unbounded; depths are capped at 254 either way.
+func MultiSourceBFS(g *Graph, sources []int32, maxDepth int) *MSBFS {
+	if len(sources) > MSBFSWidth {
+		sources = sources[:MSBFSWidth]
+	}
+	if maxDepth <= 0 || maxDepth >= unreached {
+		maxDepth = unreached - 1
+	}
+
+	n := g.NumNodes()
+	k := len(sources)
+	r := &MSBFS{Sources: sources, level: make([]uint8, n*k)}
+	for i := range r.level {
+		r.level[i] = unreached
+	}
+
+	seen := make([]uint64, n)
+	frontier := make([]uint64, n)
+	next := make([]uint64, n)
+
+	for b, s := range sources {
+		bit := uint64(1) << b
+		seen[s] |= bit
+		frontier[s] |= bit
+		r.level[int(s)*k+b] = 0
+	}
+
+	for depth := 1; depth <= maxDepth; depth++ {
+		for v := int32(0); v < int32(n); v++ {
+			f := frontier[v]
+			if f == 0 {
+				continue
+			}
+			adj, _ := g.Neighbors(v)
+			for _, u := range adj {
+				next[u] |= f
+			}
+		}
+
+		active := false
+		for u := range next {
+			fresh := next[u] &^ seen[u]
+			next[u] = 0
+			frontier[u] = fresh
+			if fresh == 0 {
+				continue
+			}
+			active = true
+			seen[u] |= fresh
+			for fresh != 0 {
+				b := bits.TrailingZeros64(fresh)
+				r.level[u*k+b] = uint8(depth)
+				fresh &= fresh - 1
+			}
+		}
+		if !active {
+			break
+		}
+	}
+	return r
+}
+
+// Dist returns the hop distance from source b to node v, or -1.
+func (r *MSBFS) Dist(b int, v int32) int {
+	l := r.level[int(v)*len(r.Sources)+b]
+	if l == unreached {
+		return -1
+	}
+	return int(l)
+}
+
+// Path returns one shortest path of node indices from source b to v, or
+// nil when v was not reached. It walks back through any neighbor one
+// level closer, so the result is deterministic for a given graph.
+func (r *MSBFS) Path(g *Graph, b int, v int32) []int32 {
+	d := r.Dist(b, v)
+	if d < 0 {
+		return nil
+	}
+
+	path := make([]int32, d+1)
+	path[d] = v
+	for cur := d; cur > 0; cur-- {
+		adj, _ := g.Neighbors(path[cur])
+		for _, u := range adj {
+			if r.Dist(b, u) == cur-1 {
+				path[cur-1] = u
+				break
+			}
+		}
+	}
+	return path
+}
@@ -0,0 +1,59 @@
+package analytics
+
+import "testing"
+
+func TestMultiSourceBFS_DistancesAndPaths(t *testing.T) {
+	// 0 - 1 - 2 - 3, plus 1 - 4 and an isolated pair 5 - 6
+	g := buildGraph(t, [][3]int{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {1, 4, 1}, {5, 6, 1}})
+
+	idx := func(id int) int32 {
+		i, ok := g.Index(id)
+		if !ok {
+			t.Fatalf("artist %d missing", id)
+		}
+		return i
+	}
+
+	r := MultiSourceBFS(g, []int32{idx(0), idx(3)}, 0)
+
+	cases := []struct {
+		source, target, want int
+	}{
+		{0, 0, 0}, {0, 3, 3}, {0, 4, 2}, {3, 4, 3}, {3, 0, 3}, {0, 6, -1},
+	}
+	for _, c := range cases {
+		b := 0
+		if c.source == 3 {
+			b = 1
+		}
+		if got := r.Dist(b, idx(c.target)); got != c.want {
+			t.Fatalf("dist %d->%d: want %d, got %d", c.source, c.target, c.want, got)
+		}
+	}
+
+	path := r.Path(g, 0, idx(3))
+	want := []int{0, 1, 2, 3}
+	if len(path) != len(want) {
+		t.Fatalf("expected path of %d nodes, got %v", len(want), path)
+	}
+	for i, v := range path {
+		if int(g.IDs[v]) != want[i] {
+			t.Fatalf("path[%d]: want %d, got %d", i, want[i], g.IDs[v])
+		}
+	}
+
+	if r.Path(g, 0, idx(6)) != nil {
+		t.Fatalf("unreached node should have no path")
+	}
+}
+
+func TestMultiSourceBFS_MaxDepth(t *testing.T) {
+	g := buildGraph(t, [][3]int{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}})
+	i0, _ := g.Index(0)
+	i3, _ := g.Index(3)
+
+	r := MultiSourceBFS(g, []int32{i0}, 2)
+	if d := r.Dist(0, i3); d != -1 {
+		t.Fatalf("expected 3 to be out of range at depth 2, got %d", d)
+	}
+}
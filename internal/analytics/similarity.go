@@ -0,0 +1,144 @@
+package analytics
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// MinHasher builds MinHash signatures of neighbor sets and folds them
+// into LSH band keys. Two artists share a band key with probability
+// J^Rows for neighbor-set Jaccard similarity J, so across Bands bands
+// the candidate threshold sits near (1/Bands)^(1/Rows).
+type MinHasher struct {
+	Bands int
+	Rows  int
+	seeds []uint64
+}
+
+// NewMinHasher returns a hasher with bands*rows hash functions. The same
+// seed always produces the same band keys, so an index can be rebuilt
+// without invalidating clients.
+func NewMinHasher(bands, rows int, seed int64) *MinHasher {
+	rng := rand.New(rand.NewSource(seed))
+	seeds := make([]uint64, bands*rows)
+	for i := range seeds {
+		seeds[i] = rng.Uint64()
+	}
+	return &MinHasher{Bands: bands, Rows: rows, seeds: seeds}
+}
+
+// mix64 is the splitmix64 finalizer: cheap and well distributed.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// BandKeys returns one LSH bucket key per band for a set of artist ids,
+// or nil for an empty set. Ids are real artist ids rather than node
+// indices, so keys are stable across graph rebuilds.
+func (m *MinHasher) BandKeys(members []int32) []int64 {
+	if len(members) == 0 {
+		return nil
+	}
+
+	sig := make([]uint64, len(m.seeds))
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+	for _, id := range members {
+		for i, s := range m.seeds {
+			if h := mix64(uint64(uint32(id)) ^ s); h < sig[i] {
+				sig[i] = h
+			}
+		}
+	}
+
+	keys := make([]int64, m.Bands)
+	for b := range keys {
+		k := uint64(b)
+		for _, v := range sig[b*m.Rows : (b+1)*m.Rows] {
+			k = mix64(k ^ v)
+		}
+		keys[b] = int64(k)
+	}
+	return keys
+}
+
+// NodeBandKeys returns the band keys of node i's neighbor set.
+func (m *MinHasher) NodeBandKeys(g *Graph, i int32) []int64 {
+	adj, _ := g.Neighbors(i)
+	members := make([]int32, len(adj))
+	for k, u := range adj {
+		members[k] = g.IDs[u]
+	}
+	return m.BandKeys(members)
+}
+
+// Similarity scores one candidate against a source artist.
+type Similarity struct {
+	ID      int
+	Overlap int     // shared collaborators
+	Cosine  float64 // cosine of shared-recording weighted neighbor vectors
+	Jaccard float64 // unweighted neighbor-set Jaccard
+}
+
+// RankSimilar rescores LSH candidates exactly. source maps each of the
+// source's neighbors to its shared-recording count; overlaps[c] holds the
+// same for candidate c restricted to neighbors it has in common with the
+// source. degree and norm are each candidate's full neighbor count and
+// L2 weight norm. Results are ordered by cosine, then overlap.
+func RankSimilar(
+	source map[int]int,
+	overlaps map[int]map[int]int,
+	degree map[int]int,
+	norm map[int]float64,
+	limit int,
+) []Similarity {
+
+	srcNorm := 0.0
+	for _, w := range source {
+		srcNorm += float64(w) * float64(w)
+	}
+	srcNorm = math.Sqrt(srcNorm)
+
+	out := make([]Similarity, 0, len(overlaps))
+	for c, shared := range overlaps {
+		if len(shared) == 0 {
+			continue
+		}
+		dot := 0.0
+		for n, w := range shared {
+			dot += float64(source[n]) * float64(w)
+		}
+
+		s := Similarity{ID: c, Overlap: len(shared)}
+		if d := srcNorm * norm[c]; d > 0 {
+			s.Cosine = dot / d
+		}
+		if union := len(source) + max(degree[c], len(shared)) - len(shared); union > 0 {
+			s.Jaccard = float64(len(shared)) / float64(union)
+		}
+		out = append(out, s)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Cosine != b.Cosine {
+			return a.Cosine > b.Cosine
+		}
+		if a.Overlap != b.Overlap {
+			return a.Overlap > b.Overlap
+		}
+		return a.ID < b.ID
+	})
+
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
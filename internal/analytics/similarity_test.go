@@ -0,0 +1,88 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBandKeys_IdenticalSetsCollide(t *testing.T) {
+	m := NewMinHasher(16, 2, 1)
+	a := m.BandKeys([]int32{5, 9, 42, 7})
+	b := m.BandKeys([]int32{42, 7, 9, 5}) // order must not matter
+
+	if len(a) != 16 {
+		t.Fatalf("expected 16 band keys, got %d", len(a))
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("band %d differs for identical sets", i)
+		}
+	}
+
+	if m.BandKeys(nil) != nil {
+		t.Fatalf("expected no keys for an empty set")
+	}
+}
+
+func TestBandKeys_DisjointSetsRarelyCollide(t *testing.T) {
+	m := NewMinHasher(16, 2, 1)
+	a := m.BandKeys([]int32{1, 2, 3, 4, 5, 6, 7, 8})
+	b := m.BandKeys([]int32{101, 102, 103, 104, 105, 106, 107, 108})
+
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	if same > 0 {
+		t.Fatalf("disjoint sets shared %d bands", same)
+	}
+}
+
+func TestNodeBandKeys_UsesArtistIDs(t *testing.T) {
+	// 10 and 11 both collaborate with exactly 1 and 2
+	g := buildGraph(t, [][3]int{{1, 10, 1}, {1, 11, 1}, {2, 10, 1}, {2, 11, 1}})
+	m := NewMinHasher(4, 2, 3)
+
+	i10, _ := g.Index(10)
+	i11, _ := g.Index(11)
+	a, b := m.NodeBandKeys(g, i10), m.NodeBandKeys(g, i11)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("band %d differs for artists with the same collaborators", i)
+		}
+	}
+
+	direct := m.BandKeys([]int32{1, 2})
+	for i := range a {
+		if a[i] != direct[i] {
+			t.Fatalf("node keys should hash artist ids, not node indices")
+		}
+	}
+}
+
+func TestRankSimilar_WeightedCosine(t *testing.T) {
+	source := map[int]int{1: 3, 2: 4} // norm 5
+	overlaps := map[int]map[int]int{
+		10: {1: 3, 2: 4}, // same vector
+		11: {1: 1},
+	}
+	degree := map[int]int{10: 2, 11: 3}
+	norm := map[int]float64{10: 5, 11: math.Sqrt(1 + 4 + 4)}
+
+	got := RankSimilar(source, overlaps, degree, norm, 0)
+	if len(got) != 2 || got[0].ID != 10 {
+		t.Fatalf("expected 10 first, got %+v", got)
+	}
+	if math.Abs(got[0].Cosine-1) > 1e-9 || math.Abs(got[0].Jaccard-1) > 1e-9 {
+		t.Fatalf("identical vectors: got cosine %v jaccard %v", got[0].Cosine, got[0].Jaccard)
+	}
+	// dot 3 / (5*3) = 0.2; jaccard 1 / (2+3-1) = 0.25
+	if math.Abs(got[1].Cosine-0.2) > 1e-9 || math.Abs(got[1].Jaccard-0.25) > 1e-9 {
+		t.Fatalf("partial overlap: got cosine %v jaccard %v", got[1].Cosine, got[1].Jaccard)
+	}
+	if got[1].Overlap != 1 {
+		t.Fatalf("expected overlap 1, got %d", got[1].Overlap)
+	}
+}
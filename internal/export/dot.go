@@ -0,0 +1,41 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDOT writes g in Graphviz DOT. Node ids are MBIDs and labels are
+// artist names; edges are labelled with their weight.
+func WriteDOT(w io.Writer, g *Graph) error {
+	bw := bufio.NewWriter(w)
+
+	kind, arrow := "graph", "--"
+	if g.Directed {
+		kind, arrow = "digraph", "->"
+	}
+
+	fmt.Fprintf(bw, "%s melodymap {\n", kind)
+	for _, n := range g.Nodes {
+		fmt.Fprintf(bw, "  %s [label=%s, mbid=%s];\n", dotQuote(n.ID), dotQuote(n.Name), dotQuote(n.ID))
+	}
+	for _, e := range g.Edges {
+		attrs := make([]string, 0, len(edgeAttrs)+1)
+		attrs = append(attrs, "label="+dotQuote(fmt.Sprint(e.Weight)))
+		for _, a := range edgeAttrs {
+			attrs = append(attrs, a.Key+"="+dotQuote(a.Value(e)))
+		}
+		fmt.Fprintf(bw, "  %s %s %s [%s];\n", dotQuote(e.Source), arrow, dotQuote(e.Target), strings.Join(attrs, ", "))
+	}
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
@@ -0,0 +1,149 @@
+// Package export serializes search paths and collaboration subgraphs into
+// formats graph tools understand: GraphML, GEXF (Gephi) and DOT (Graphviz).
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/search"
+)
+
+// Supported values of the `format` query parameter.
+const (
+	FormatJSON    = "json"
+	FormatGraphML = "graphml"
+	FormatGEXF    = "gexf"
+	FormatDOT     = "dot"
+)
+
+// Graph is the format-neutral model every serializer works from.
+type Graph struct {
+	Directed bool
+	Nodes    []Node
+	Edges    []Edge
+}
+
+type Node struct {
+	ID   string // MBID
+	Name string
+}
+
+type Edge struct {
+	Source string
+	Target string
+	Weight int
+	Tracks []search.TrackInfo
+}
+
+// IsGraphFormat reports whether format is one of the graph serializations.
+func IsGraphFormat(format string) bool {
+	switch strings.ToLower(format) {
+	case FormatGraphML, FormatGEXF, FormatDOT:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the MIME type and file extension for a format.
+func ContentType(format string) (string, string) {
+	switch strings.ToLower(format) {
+	case FormatGraphML:
+		return "application/graphml+xml", "graphml"
+	case FormatGEXF:
+		return "application/gexf+xml", "gexf"
+	case FormatDOT:
+		return "text/vnd.graphviz", "dot"
+	}
+	return "application/json", "json"
+}
+
+// Write serializes g in the requested format.
+func Write(w io.Writer, format string, g *Graph) error {
+	switch strings.ToLower(format) {
+	case FormatGraphML:
+		return WriteGraphML(w, g)
+	case FormatGEXF:
+		return WriteGEXF(w, g)
+	case FormatDOT:
+		return WriteDOT(w, g)
+	}
+	return fmt.Errorf("unsupported export format %q", format)
+}
+
+// FromSearchResponse turns a found path into a directed chain graph. Steps
+// without MBIDs (older results) fall back to artist names as node IDs.
+func FromSearchResponse(resp search.SearchResponse) *Graph {
+	g := &Graph{Directed: true}
+	seen := make(map[string]bool)
+
+	addNode := func(id, name string) string {
+		if id == "" {
+			id = name
+		}
+		if !seen[id] {
+			seen[id] = true
+			g.Nodes = append(g.Nodes, Node{ID: id, Name: name})
+		}
+		return id
+	}
+
+	for _, st := range resp.Path {
+		from := addNode(st.FromID, st.From)
+		to := addNode(st.ToID, st.To)
+
+		weight := st.SharedCount
+		if weight == 0 {
+			weight = len(st.Tracks)
+		}
+		g.Edges = append(g.Edges, Edge{
+			Source: from,
+			Target: to,
+			Weight: weight,
+			Tracks: st.Tracks,
+		})
+	}
+	return g
+}
+
+//
+// ============================================================
+// Edge attribute helpers shared by the serializers
+// ============================================================
+//
+
+// edgeAttr is one named attribute every serializer writes on edges.
+type edgeAttr struct {
+	Key   string
+	Type  string // GraphML / GEXF attribute type
+	Value func(e Edge) string
+}
+
+var edgeAttrs = []edgeAttr{
+	{"weight", "int", func(e Edge) string { return strconv.Itoa(e.Weight) }},
+	{"tracks", "string", func(e Edge) string {
+		return joinTracks(e.Tracks, func(t search.TrackInfo) string { return t.Name })
+	}},
+	{"track_ids", "string", func(e Edge) string {
+		return joinTracks(e.Tracks, func(t search.TrackInfo) string { return t.ID })
+	}},
+	{"recording_ids", "string", func(e Edge) string {
+		return joinTracks(e.Tracks, func(t search.TrackInfo) string { return t.RecordingID })
+	}},
+	{"releases", "string", func(e Edge) string {
+		return joinTracks(e.Tracks, func(t search.TrackInfo) string { return t.ReleaseTitle })
+	}},
+}
+
+// joinTracks joins one field of every track with " | ", skipping blanks.
+func joinTracks(ts []search.TrackInfo, field func(search.TrackInfo) string) string {
+	parts := make([]string, 0, len(ts))
+	for _, t := range ts {
+		if v := field(t); v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, " | ")
+}
@@ -0,0 +1,124 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/search"
+)
+
+func samplePath() search.SearchResponse {
+	return search.SearchResponse{
+		Start:  "Artist A",
+		Target: "Artist C",
+		Hops:   2,
+		Path: []search.Step{
+			{
+				From: "Artist A", To: "Artist B", FromID: "mbid-a", ToID: "mbid-b",
+				Tracks: []search.TrackInfo{
+					{ID: "t1", Name: "Song & Dance", RecordingID: "r1", ReleaseTitle: "LP"},
+					{ID: "t2", Name: "Another", RecordingID: "r2"},
+				},
+			},
+			{
+				From: "Artist B", To: "Artist \"C\"", FromID: "mbid-b", ToID: "mbid-c",
+				SharedCount: 7,
+				Tracks:      []search.TrackInfo{{ID: "t3", Name: "Third", RecordingID: "r3"}},
+			},
+		},
+	}
+}
+
+func TestFromSearchResponse_BuildsChain(t *testing.T) {
+	g := FromSearchResponse(samplePath())
+
+	if !g.Directed {
+		t.Fatalf("expected a directed path graph")
+	}
+	if len(g.Nodes) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(g.Nodes))
+	}
+	if g.Nodes[0].ID != "mbid-a" || g.Nodes[0].Name != "Artist A" {
+		t.Fatalf("unexpected first node: %+v", g.Nodes[0])
+	}
+	if len(g.Edges) != 2 {
+		t.Fatalf("expected 2 edges, got %d", len(g.Edges))
+	}
+	if g.Edges[0].Weight != 2 {
+		t.Fatalf("expected track count as weight without SharedCount, got %d", g.Edges[0].Weight)
+	}
+	if g.Edges[1].Weight != 7 {
+		t.Fatalf("expected SharedCount as weight, got %d", g.Edges[1].Weight)
+	}
+}
+
+func TestFromSearchResponse_FallsBackToNames(t *testing.T) {
+	g := FromSearchResponse(search.SearchResponse{
+		Path: []search.Step{{From: "A", To: "B"}},
+	})
+	if g.Nodes[0].ID != "A" || g.Edges[0].Target != "B" {
+		t.Fatalf("expected names as ids, got %+v / %+v", g.Nodes, g.Edges)
+	}
+}
+
+func TestWriteGraphML_WellFormed(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGraphML(&buf, FromSearchResponse(samplePath())); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc graphML
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid GraphML: %v\n%s", err, buf.String())
+	}
+	if len(doc.Graph.Nodes) != 3 || len(doc.Graph.Edges) != 2 {
+		t.Fatalf("unexpected counts: %d nodes, %d edges", len(doc.Graph.Nodes), len(doc.Graph.Edges))
+	}
+	if !strings.Contains(buf.String(), "Song &amp; Dance | Another") {
+		t.Fatalf("expected escaped track evidence on edge:\n%s", buf.String())
+	}
+}
+
+func TestWriteGEXF_WellFormed(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGEXF(&buf, FromSearchResponse(samplePath())); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc gexf
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid GEXF: %v\n%s", err, buf.String())
+	}
+	if len(doc.Graph.Nodes) != 3 || len(doc.Graph.Edges) != 2 {
+		t.Fatalf("unexpected counts: %d nodes, %d edges", len(doc.Graph.Nodes), len(doc.Graph.Edges))
+	}
+	if doc.Graph.Edges[1].Weight != "7" {
+		t.Fatalf("expected native edge weight 7, got %q", doc.Graph.Edges[1].Weight)
+	}
+}
+
+func TestWriteDOT_QuotesAndDirection(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, FromSearchResponse(samplePath())); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	if !strings.HasPrefix(out, "digraph melodymap {") {
+		t.Fatalf("expected digraph header, got:\n%s", out)
+	}
+	if !strings.Contains(out, `"mbid-a" -> "mbid-b"`) {
+		t.Fatalf("missing edge a->b:\n%s", out)
+	}
+	if !strings.Contains(out, `label="Artist \"C\""`) {
+		t.Fatalf("expected escaped quote in label:\n%s", out)
+	}
+}
+
+func TestWrite_RejectsUnknownFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, "csv", &Graph{}); err == nil {
+		t.Fatalf("expected error for unknown format")
+	}
+}
@@ -0,0 +1,109 @@
+package export
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes g as GraphML. Nodes carry their MBID (as the node
+// id and an "mbid" attribute) and name; edges carry weight and tracks.
+func WriteGraphML(w io.Writer, g *Graph) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "name", For: "node", AttrName: "name", AttrType: "string"},
+			{ID: "mbid", For: "node", AttrName: "mbid", AttrType: "string"},
+		},
+		Graph: graphMLGraph{
+			ID:          "melodymap",
+			EdgeDefault: "undirected",
+		},
+	}
+	if g.Directed {
+		doc.Graph.EdgeDefault = "directed"
+	}
+
+	for _, a := range edgeAttrs {
+		doc.Keys = append(doc.Keys, graphMLKey{ID: a.Key, For: "edge", AttrName: a.Key, AttrType: a.Type})
+	}
+
+	for _, n := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: n.ID,
+			Data: []graphMLData{
+				{Key: "name", Value: n.Name},
+				{Key: "mbid", Value: n.ID},
+			},
+		})
+	}
+
+	for i, e := range g.Edges {
+		ge := graphMLEdge{
+			ID:     edgeID(i),
+			Source: e.Source,
+			Target: e.Target,
+		}
+		for _, a := range edgeAttrs {
+			ge.Data = append(ge.Data, graphMLData{Key: a.Key, Value: a.Value(e)})
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, ge)
+	}
+
+	return writeXML(w, doc)
+}
+
+func writeXML(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func edgeID(i int) string {
+	return "e" + strconv.Itoa(i)
+}
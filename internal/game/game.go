@@ -0,0 +1,101 @@
+// Package game holds the rules of the daily challenge: which pair is
+// played on a given day, what counts as a well-formed answer and how an
+// answer is scored. Persistence lives on search.Store; HTTP in main.
+package game
+
+import (
+	"hash/fnv"
+	"os"
+	"strconv"
+	"time"
+)
+
+// DateLayout is how puzzle days appear in URLs and JSON.
+const DateLayout = "2006-01-02"
+
+// MaxScore is awarded for a valid chain of optimal length.
+const MaxScore = 100
+
+// MaxChainLength bounds a submitted chain, start and target included.
+const MaxChainLength = 20
+
+// Band is the range of optimal hop counts a daily pair is drawn from.
+type Band struct {
+	Min int
+	Max int
+}
+
+// DefaultBand keeps puzzles solvable but not trivial.
+var DefaultBand = Band{Min: 3, Max: 5}
+
+// BandFromEnv reads GAME_MIN_HOPS / GAME_MAX_HOPS, falling back to
+// DefaultBand for missing or nonsensical values.
+func BandFromEnv() Band {
+	b := DefaultBand
+	if v, err := strconv.Atoi(os.Getenv("GAME_MIN_HOPS")); err == nil && v > 0 {
+		b.Min = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("GAME_MAX_HOPS")); err == nil && v > 0 {
+		b.Max = v
+	}
+	if b.Max < b.Min {
+		b = DefaultBand
+	}
+	return b
+}
+
+// Day truncates t to its UTC calendar day, the unit puzzles are keyed by.
+func Day(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// PickIndex deterministically maps a day onto one of n candidate pairs.
+// The same day always yields the same index for the same n.
+func PickIndex(day time.Time, n int) int {
+	if n <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	h.Write([]byte("melodymap-daily:" + Day(day).Format(DateLayout)))
+	return int(h.Sum64() % uint64(n))
+}
+
+// CheckChain validates the shape of a resolved chain of artist ids: it
+// must run from start to target without revisiting anyone. Hop validity
+// is checked separately against the collaboration data. It returns a
+// short machine-readable reason when the chain is rejected.
+func CheckChain(ids []int, start, target int) (string, bool) {
+	if len(ids) < 2 {
+		return "chain_too_short", false
+	}
+	if len(ids) > MaxChainLength {
+		return "chain_too_long", false
+	}
+	if ids[0] != start {
+		return "wrong_start", false
+	}
+	if ids[len(ids)-1] != target {
+		return "wrong_target", false
+	}
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return "repeated_artist", false
+		}
+		seen[id] = true
+	}
+	return "", true
+}
+
+// Score rates a valid chain of `hops` hops against the optimal length:
+// MaxScore at optimal, falling off in proportion to the extra hops.
+func Score(optimal, hops int) int {
+	if hops <= 0 || optimal <= 0 {
+		return 0
+	}
+	if hops <= optimal {
+		return MaxScore
+	}
+	return MaxScore * optimal / hops
+}
@@ -0,0 +1,58 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPickIndex_StablePerDay(t *testing.T) {
+	morning := time.Date(2026, 3, 14, 1, 0, 0, 0, time.UTC)
+	evening := time.Date(2026, 3, 14, 23, 0, 0, 0, time.UTC)
+
+	if PickIndex(morning, 1000) != PickIndex(evening, 1000) {
+		t.Fatalf("same day should pick the same pair")
+	}
+
+	distinct := map[int]bool{}
+	for d := 0; d < 30; d++ {
+		i := PickIndex(morning.AddDate(0, 0, d), 1000)
+		if i < 0 || i >= 1000 {
+			t.Fatalf("index %d out of range", i)
+		}
+		distinct[i] = true
+	}
+	if len(distinct) < 20 {
+		t.Fatalf("expected days to spread over candidates, got %d distinct picks", len(distinct))
+	}
+}
+
+func TestCheckChain(t *testing.T) {
+	cases := []struct {
+		ids    []int
+		reason string
+	}{
+		{[]int{1, 2, 3}, ""},
+		{[]int{1}, "chain_too_short"},
+		{[]int{2, 3}, "wrong_start"},
+		{[]int{1, 2}, "wrong_target"},
+		{[]int{1, 2, 1, 3}, "repeated_artist"},
+	}
+	for _, c := range cases {
+		reason, ok := CheckChain(c.ids, 1, 3)
+		if reason != c.reason || ok != (c.reason == "") {
+			t.Fatalf("%v: want %q, got %q (ok=%v)", c.ids, c.reason, reason, ok)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	if got := Score(3, 3); got != MaxScore {
+		t.Fatalf("optimal chain: want %d, got %d", MaxScore, got)
+	}
+	if got := Score(3, 6); got != 50 {
+		t.Fatalf("double length: want 50, got %d", got)
+	}
+	if got := Score(3, 0); got != 0 {
+		t.Fatalf("empty chain: want 0, got %d", got)
+	}
+}
@@ -27,6 +27,11 @@ type Job struct {
 	// Add fields so CreateJob is valid:
 	Start  string `json:"start"`
 	Target string `json:"target"`
+
+	// Progress is 0..1 for jobs that can report it; Stage describes
+	// what the job is currently doing.
+	Progress float64 `json:"progress"`
+	Stage    string  `json:"stage,omitempty"`
 }
 
 type JobManager struct {
@@ -51,3 +51,11 @@ func (m *ManagerStruct) Get(id string) (*Job, bool) {
 	job, ok := m.jobs[id]
 	return job, ok
 }
+
+// SetProgress records how far along a job is.
+func (m *ManagerStruct) SetProgress(id string, progress float64, stage string) {
+	m.Update(id, func(j *Job) {
+		j.Progress = progress
+		j.Stage = stage
+	})
+}
@@ -0,0 +1,119 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+)
+
+//
+// ========================================================================
+// Ambiguous names
+// ========================================================================
+//
+
+// ResolveAmbiguityMargin is how far, relative to the best score, another
+// candidate may trail and still count as a comparable match.
+const ResolveAmbiguityMargin = 0.1
+
+// ArtistChoice is one candidate offered back to the client when a name is
+// ambiguous, with enough context to tell homonyms apart.
+type ArtistChoice struct {
+	MBID      string  `json:"mbid"`
+	Name      string  `json:"name"`
+	Comment   string  `json:"comment,omitempty"` // MusicBrainz disambiguation
+	Country   string  `json:"country,omitempty"`
+	BeginYear *int    `json:"beginYear,omitempty"`
+	EndYear   *int    `json:"endYear,omitempty"`
+	Degree    int     `json:"degree"`
+	Score     float64 `json:"score"`
+
+	id int
+}
+
+// AmbiguousArtist returns the comparable candidates for a typed name, best
+// first, or nil when the name resolves clearly (or not at all).
+func (s *Store) AmbiguousArtist(ctx context.Context, name string) ([]ArtistChoice, error) {
+	res, err := s.ResolveArtist(ctx, name, ResolveDefaultLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	cands := comparableCandidates(res.Candidates)
+	if len(cands) < 2 {
+		return nil, nil
+	}
+
+	out := make([]ArtistChoice, len(cands))
+	ids := make([]int, len(cands))
+	for i, c := range cands {
+		out[i] = ArtistChoice{
+			MBID:    c.MBID,
+			Name:    c.Name,
+			Comment: c.Comment,
+			Degree:  c.Degree,
+			Score:   c.Score,
+			id:      c.ID,
+		}
+		ids[i] = c.ID
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT a.id, COALESCE(iso.code, ''), a.begin_date_year, a.end_date_year
+		FROM artist a
+		LEFT JOIN LATERAL (
+			SELECT code FROM iso_3166_1 WHERE area = a.area LIMIT 1
+		) iso ON true
+		WHERE a.id = ANY($1);
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := make(map[int]*ArtistChoice, len(out))
+	for i := range out {
+		byID[out[i].id] = &out[i]
+	}
+	for rows.Next() {
+		var (
+			id         int
+			country    string
+			begin, end sql.NullInt32
+		)
+		if err := rows.Scan(&id, &country, &begin, &end); err != nil {
+			return nil, err
+		}
+		c := byID[id]
+		if c == nil {
+			continue
+		}
+		c.Country = country
+		if begin.Valid {
+			y := int(begin.Int32)
+			c.BeginYear = &y
+		}
+		if end.Valid {
+			y := int(end.Int32)
+			c.EndYear = &y
+		}
+	}
+	return out, rows.Err()
+}
+
+// comparableCandidates keeps the ranked candidates that are acceptable
+// name matches and score within ResolveAmbiguityMargin of the best one.
+func comparableCandidates(ranked []ArtistCandidate) []ArtistCandidate {
+	if len(ranked) == 0 || ranked[0].NameScore < ResolveAcceptNameScore {
+		return nil
+	}
+
+	floor := ranked[0].Score * (1 - ResolveAmbiguityMargin)
+	var out []ArtistCandidate
+	for _, c := range ranked {
+		if c.Score < floor || c.NameScore < ResolveAcceptNameScore {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
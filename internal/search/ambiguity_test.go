@@ -0,0 +1,41 @@
+package search
+
+import "testing"
+
+func TestComparableCandidates_Homonyms(t *testing.T) {
+	cands := []ArtistCandidate{
+		{ID: 10, Name: "John Williams", Comment: "British guitarist", Trigram: 1, Degree: 20},
+		{ID: 11, Name: "John Williams", Comment: "US composer", Trigram: 1, Degree: 900},
+		{ID: 12, Name: "John Williamson", Trigram: 0.7, Degree: 40},
+	}
+	ranked, _ := rankCandidates("john williams", cands)
+
+	got := comparableCandidates(ranked)
+	if len(got) != 2 || got[0].ID != 11 || got[1].ID != 10 {
+		t.Fatalf("expected both homonyms, best first, got %+v", got)
+	}
+}
+
+func TestComparableCandidates_ClearWinner(t *testing.T) {
+	cands := []ArtistCandidate{
+		{ID: 1, Name: "Beatles Revival Band", Trigram: 0.5, Degree: 3},
+		{ID: 2, Name: "The Beatles", Trigram: 0.6, Degree: 400},
+	}
+	ranked, _ := rankCandidates("beatles", cands)
+
+	if got := comparableCandidates(ranked); len(got) != 1 || got[0].ID != 2 {
+		t.Fatalf("expected only The Beatles, got %+v", got)
+	}
+}
+
+func TestComparableCandidates_NoAcceptableMatch(t *testing.T) {
+	cands := []ArtistCandidate{
+		{ID: 1, Name: "Completely Different", Trigram: 0.3},
+		{ID: 2, Name: "Also Unrelated", Trigram: 0.3},
+	}
+	ranked, _ := rankCandidates("zzz", cands)
+
+	if got := comparableCandidates(ranked); got != nil {
+		t.Fatalf("expected no candidates, got %+v", got)
+	}
+}
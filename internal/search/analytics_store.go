@@ -0,0 +1,335 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/analytics"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+//
+// ========================================================================
+// Analytics tables (written by the offline cmd/ programs)
+// ========================================================================
+//
+
+// analyticsWriteBatch is how many rows go into one unnest() insert.
+const analyticsWriteBatch = 50000
+
+// MigrateAnalytics creates the tables the offline analytics jobs fill.
+// It is cheap and idempotent, so the commands call it before writing.
+func (s *Store) MigrateAnalytics(ctx context.Context) error {
+	q := `
+		CREATE TABLE IF NOT EXISTS artist_community (
+			artist_id INT PRIMARY KEY,
+			community_id INT NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS artist_community_community_idx
+			ON artist_community (community_id);
+
+		CREATE TABLE IF NOT EXISTS community_summary (
+			community_id INT PRIMARY KEY,
+			size INT NOT NULL,
+			top_artists INT[] NOT NULL,
+			dominant_tags TEXT[] NOT NULL DEFAULT '{}',
+			computed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		);
+
+		CREATE TABLE IF NOT EXISTS artist_centrality (
+			artist_id INT PRIMARY KEY,
+			pagerank DOUBLE PRECISION NOT NULL,
+			degree INT NOT NULL,
+			betweenness DOUBLE PRECISION NOT NULL,
+			popularity REAL NOT NULL DEFAULT 0, -- pagerank percentile, 0..100
+			computed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		);
+
+		CREATE TABLE IF NOT EXISTS artist_lsh_bucket (
+			band SMALLINT NOT NULL,
+			bucket BIGINT NOT NULL,
+			artist_id INT NOT NULL,
+			PRIMARY KEY (band, bucket, artist_id)
+		);
+
+		CREATE INDEX IF NOT EXISTS artist_lsh_bucket_artist_idx
+			ON artist_lsh_bucket (artist_id);
+
+		CREATE TABLE IF NOT EXISTS top_artist (
+			artist_id INT PRIMARY KEY,
+			rank INT NOT NULL,
+			name TEXT NOT NULL
+		);
+
+		-- start_id < target_id; path runs start -> target, NULL distance = unreachable
+		CREATE TABLE IF NOT EXISTS artist_pair_distance (
+			start_id INT NOT NULL,
+			target_id INT NOT NULL,
+			distance SMALLINT,
+			path INT[] NOT NULL DEFAULT '{}',
+			data_version BIGINT NOT NULL,
+			computed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			PRIMARY KEY (start_id, target_id)
+		);
+
+		CREATE INDEX IF NOT EXISTS artist_pair_distance_distance_idx
+			ON artist_pair_distance (distance DESC NULLS LAST);
+	`
+	if _, err := s.DB.ExecContext(ctx, q); err != nil {
+		return err
+	}
+
+	// the pair matrix records the data version it was computed against
+	return s.migratePathCache(ctx)
+}
+
+// StreamCollabEdges calls fn for every row of artist_collab_summary in
+// artist_id order. It reads inside a read-only REPEATABLE READ
+// transaction so long-running jobs see one consistent snapshot.
+func (s *Store) StreamCollabEdges(
+	ctx context.Context,
+	fn func(artistID, neighborID, shared int) error,
+) error {
+
+	tx, err := s.DB.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT artist_id, neighbor_artist_id, shared_recordings
+		FROM artist_collab_summary
+		ORDER BY artist_id, neighbor_artist_id;
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a, nb, shared int
+		if err := rows.Scan(&a, &nb, &shared); err != nil {
+			return err
+		}
+		if err := fn(a, nb, shared); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// LoadCollabGraph streams artist_collab_summary into an in-memory graph
+// for the offline analytics jobs. progress, if set, is called every
+// million edges.
+func (s *Store) LoadCollabGraph(ctx context.Context, progress func(edges int)) (*analytics.Graph, error) {
+	return LoadCollabGraph(ctx, s, progress)
+}
+
+//
+// ========================================================================
+// Communities
+// ========================================================================
+//
+
+// CommunityRow is one community as written by cmd/communities.
+type CommunityRow struct {
+	ID           int32
+	Size         int
+	TopArtistIDs []int32
+}
+
+// CommunityInfo is a community as served by the API.
+type CommunityInfo struct {
+	ID           int         `json:"id"`
+	Size         int         `json:"size"`
+	TopArtists   []ArtistRef `json:"topArtists"`
+	DominantTags []string    `json:"dominantTags"`
+}
+
+// SaveCommunities replaces all community data in one transaction, then
+// derives each community's dominant tags from its members' artist_tag rows.
+func (s *Store) SaveCommunities(
+	ctx context.Context,
+	artistIDs, communityIDs []int32,
+	communities []CommunityRow,
+) error {
+
+	if len(artistIDs) != len(communityIDs) {
+		return fmt.Errorf("artist/community length mismatch: %d vs %d", len(artistIDs), len(communityIDs))
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `TRUNCATE artist_community, community_summary;`); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(artistIDs); i += analyticsWriteBatch {
+		end := min(i+analyticsWriteBatch, len(artistIDs))
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO artist_community (artist_id, community_id)
+			SELECT * FROM unnest($1::int[], $2::int[]);
+		`, artistIDs[i:end], communityIDs[i:end])
+		if err != nil {
+			return err
+		}
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO community_summary (community_id, size, top_artists)
+		VALUES ($1, $2, $3);
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, c := range communities {
+		if _, err := stmt.ExecContext(ctx, c.ID, c.Size, c.TopArtistIDs); err != nil {
+			return err
+		}
+	}
+
+	tags := `
+		UPDATE community_summary cs
+		SET dominant_tags = t.tags
+		FROM (
+			SELECT community_id, array_agg(name ORDER BY score DESC) AS tags
+			FROM (
+				SELECT
+					ac.community_id,
+					tg.name,
+					sum(at.count) AS score,
+					row_number() OVER (
+						PARTITION BY ac.community_id
+						ORDER BY sum(at.count) DESC
+					) AS rn
+				FROM artist_community ac
+				JOIN artist_tag at ON at.artist = ac.artist_id
+				JOIN tag tg        ON tg.id = at.tag
+				GROUP BY ac.community_id, tg.name
+			) ranked
+			WHERE rn <= 5
+			GROUP BY community_id
+		) t
+		WHERE t.community_id = cs.community_id;
+	`
+	if _, err := tx.ExecContext(ctx, tags); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CommunitiesForArtists returns the community id of each MBID that has one.
+func (s *Store) CommunitiesForArtists(ctx context.Context, mbids []string) (map[string]int, error) {
+	out := make(map[string]int, len(mbids))
+	if len(mbids) == 0 {
+		return out, nil
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT a.gid::text, ac.community_id
+		FROM artist a
+		JOIN artist_community ac ON ac.artist_id = a.id
+		WHERE a.gid = ANY($1::uuid[]);
+	`, mbids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var mbid string
+		var id int
+		if err := rows.Scan(&mbid, &id); err != nil {
+			return nil, err
+		}
+		out[mbid] = id
+	}
+	return out, rows.Err()
+}
+
+// GetCommunities loads community summaries by id, resolving top artists.
+func (s *Store) GetCommunities(ctx context.Context, ids []int) (map[int]*CommunityInfo, error) {
+	return s.queryCommunities(ctx, `
+		SELECT community_id, size, top_artists, dominant_tags
+		FROM community_summary
+		WHERE community_id = ANY($1);
+	`, ids)
+}
+
+// TopCommunities returns the largest communities.
+func (s *Store) TopCommunities(ctx context.Context, limit int) ([]*CommunityInfo, error) {
+	byID, err := s.queryCommunities(ctx, `
+		SELECT community_id, size, top_artists, dominant_tags
+		FROM community_summary
+		ORDER BY size DESC
+		LIMIT $1;
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*CommunityInfo, 0, len(byID))
+	for _, c := range byID {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Size > out[j].Size })
+	return out, nil
+}
+
+func (s *Store) queryCommunities(ctx context.Context, q string, arg any) (map[int]*CommunityInfo, error) {
+	rows, err := s.DB.QueryContext(ctx, q, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	m := pgtype.NewMap()
+	out := make(map[int]*CommunityInfo)
+	topIDs := make(map[int][]int)
+	var allIDs []int
+
+	for rows.Next() {
+		c := &CommunityInfo{}
+		var top []int
+		if err := rows.Scan(&c.ID, &c.Size, m.SQLScanner(&top), m.SQLScanner(&c.DominantTags)); err != nil {
+			return nil, err
+		}
+		out[c.ID] = c
+		topIDs[c.ID] = top
+		allIDs = append(allIDs, top...)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	refs, err := s.ArtistRefsByID(ctx, allIDs)
+	if err != nil {
+		return nil, err
+	}
+	for id, c := range out {
+		c.TopArtists = make([]ArtistRef, 0, len(topIDs[id]))
+		for _, aid := range topIDs[id] {
+			if ref, ok := refs[aid]; ok {
+				c.TopArtists = append(c.TopArtists, ref)
+			}
+		}
+		if c.DominantTags == nil {
+			c.DominantTags = []string{}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,201 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+//
+// ========================================================================
+// Artist profile
+// ========================================================================
+//
+
+const (
+	// ProfileDefaultTop and ProfileMaxTop bound the top collaborators listed.
+	ProfileDefaultTop = 10
+	ProfileMaxTop     = 50
+
+	profileMaxTags = 10
+)
+
+// ArtistTag is a folksonomy tag with its vote count.
+type ArtistTag struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// TopCollaborator is one neighbor in an artist profile.
+type TopCollaborator struct {
+	ArtistRef
+	SharedRecordings int `json:"sharedRecordings"`
+	SharedReleases   int `json:"sharedReleases"`
+	FirstYear        int `json:"firstYear,omitempty"`
+	LastYear         int `json:"lastYear,omitempty"`
+}
+
+// ArtistProfile is everything the UI shows about one artist. Dates are
+// MusicBrainz partial dates: "1962", "1962-10" or "1962-10-05".
+type ArtistProfile struct {
+	MBID      string      `json:"mbid"`
+	Name      string      `json:"name"`
+	SortName  string      `json:"sortName"`
+	Comment   string      `json:"comment,omitempty"` // MusicBrainz disambiguation
+	Type      string      `json:"type,omitempty"`
+	Gender    string      `json:"gender,omitempty"`
+	Area      string      `json:"area,omitempty"`
+	Country   string      `json:"country,omitempty"`
+	BeginDate string      `json:"beginDate,omitempty"`
+	EndDate   string      `json:"endDate,omitempty"`
+	Ended     bool        `json:"ended"`
+	Tags      []ArtistTag `json:"tags"`
+	ImageURL  string      `json:"imageURL,omitempty"`
+	SpotifyID string      `json:"spotifyID,omitempty"`
+
+	Collaborators    int               `json:"collaborators"`    // distinct co-credited artists
+	CollabRecordings int               `json:"collabRecordings"` // recordings shared with anyone
+	TopCollaborators []TopCollaborator `json:"topCollaborators"`
+}
+
+// ArtistProfile loads the profile of an artist, following MBID redirects.
+// top bounds the collaborators listed. Unknown MBIDs yield sql.ErrNoRows.
+func (s *Store) ArtistProfile(ctx context.Context, mbid string, top int) (*ArtistProfile, error) {
+	if top <= 0 {
+		top = ProfileDefaultTop
+	}
+	top = min(top, ProfileMaxTop)
+
+	p := &ArtistProfile{Tags: []ArtistTag{}, TopCollaborators: []TopCollaborator{}}
+	var (
+		id                                int
+		begin, end                        [3]sql.NullInt16
+		typ, gender, area, country, image sql.NullString
+	)
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT
+			a.id, a.gid::text, a.name, a.sort_name, a.comment,
+			t.name, g.name, ar.name,
+			(SELECT code FROM iso_3166_1 WHERE area = a.area LIMIT 1),
+			a.begin_date_year, a.begin_date_month, a.begin_date_day,
+			a.end_date_year, a.end_date_month, a.end_date_day,
+			a.ended,
+			(
+				SELECT u.url
+				FROM l_artist_url l
+				JOIN link lk ON lk.id = l.link
+				JOIN link_type lt ON lt.id = lk.link_type
+				JOIN url u ON u.id = l.entity1
+				WHERE l.entity0 = a.id AND lt.name = 'image'
+				ORDER BY l.id
+				LIMIT 1
+			)
+		FROM artist a
+		LEFT JOIN artist_type t ON t.id = a.type
+		LEFT JOIN gender g ON g.id = a.gender
+		LEFT JOIN area ar ON ar.id = a.area
+		WHERE a.id = `+artistIDByGID("$1")+`;
+	`, mbid).Scan(
+		&id, &p.MBID, &p.Name, &p.SortName, &p.Comment,
+		&typ, &gender, &area, &country,
+		&begin[0], &begin[1], &begin[2],
+		&end[0], &end[1], &end[2],
+		&p.Ended, &image,
+	)
+	if err != nil {
+		return nil, err
+	}
+	p.Type, p.Gender, p.Area, p.Country = typ.String, gender.String, area.String, country.String
+	p.BeginDate = partialDate(begin)
+	p.EndDate = partialDate(end)
+	p.ImageURL = imageURL(image.String)
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT tg.name, at.count
+		FROM artist_tag at
+		JOIN tag tg ON tg.id = at.tag
+		WHERE at.artist = $1 AND at.count > 0
+		ORDER BY at.count DESC, tg.name
+		LIMIT $2;
+	`, id, profileMaxTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t ArtistTag
+		if err := rows.Scan(&t.Name, &t.Count); err != nil {
+			return nil, err
+		}
+		p.Tags = append(p.Tags, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = s.DB.QueryRowContext(ctx, `
+		SELECT
+			(SELECT count(*) FROM artist_collab_summary WHERE artist_id = $1),
+			(SELECT count(DISTINCT recording_id) FROM artist_collab WHERE artist_id = $1);
+	`, id).Scan(&p.Collaborators, &p.CollabRecordings)
+	if err != nil {
+		return nil, err
+	}
+
+	sums, err := s.ListCollabSummaries(ctx, p.MBID, top)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range sums {
+		p.TopCollaborators = append(p.TopCollaborators, TopCollaborator{
+			ArtistRef:        ArtistRef{MBID: c.NeighborMBID, Name: c.NeighborName},
+			SharedRecordings: c.SharedRecordings,
+			SharedReleases:   c.SharedReleases,
+			FirstYear:        c.FirstYear,
+			LastYear:         c.LastYear,
+		})
+	}
+
+	// a missing crosswalk table only costs the Spotify link
+	ids, err := s.SpotifyIDsForMBIDs(ctx, CrosswalkArtist, []string{p.MBID})
+	if err == nil {
+		p.SpotifyID = ids[p.MBID]
+	}
+
+	return p, nil
+}
+
+// partialDate formats a MusicBrainz year/month/day triple, any suffix of
+// which may be missing.
+func partialDate(d [3]sql.NullInt16) string {
+	if !d[0].Valid {
+		return ""
+	}
+	out := fmt.Sprintf("%04d", d[0].Int16)
+	for _, part := range d[1:] {
+		if !part.Valid {
+			break
+		}
+		out += fmt.Sprintf("-%02d", part.Int16)
+	}
+	return out
+}
+
+// imageURL turns the URL of an artist's image relationship into one that
+// serves the image itself. Wikimedia Commons file pages are rewritten to
+// Special:FilePath, which redirects to the file.
+func imageURL(raw string) string {
+	const commonsFile = "commons.wikimedia.org/wiki/File:"
+
+	i := strings.Index(raw, commonsFile)
+	if i < 0 {
+		return raw
+	}
+	name := raw[i+len(commonsFile):]
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	return "https://commons.wikimedia.org/wiki/Special:FilePath/" + url.PathEscape(name)
+}
@@ -0,0 +1,40 @@
+package search
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestPartialDate(t *testing.T) {
+	n := func(v int16) sql.NullInt16 { return sql.NullInt16{Int16: v, Valid: true} }
+	var none sql.NullInt16
+
+	cases := []struct {
+		in   [3]sql.NullInt16
+		want string
+	}{
+		{[3]sql.NullInt16{n(1962), n(10), n(5)}, "1962-10-05"},
+		{[3]sql.NullInt16{n(1962), n(10), none}, "1962-10"},
+		{[3]sql.NullInt16{n(1962), none, n(5)}, "1962"},
+		{[3]sql.NullInt16{none, n(10), n(5)}, ""},
+	}
+	for _, c := range cases {
+		if got := partialDate(c.in); got != c.want {
+			t.Fatalf("partialDate(%v) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestImageURL(t *testing.T) {
+	cases := map[string]string{
+		"https://commons.wikimedia.org/wiki/File:The_Beatles_1964.jpg": "https://commons.wikimedia.org/wiki/Special:FilePath/The_Beatles_1964.jpg",
+		"https://commons.wikimedia.org/wiki/File:Bj%C3%B6rk.jpg":       "https://commons.wikimedia.org/wiki/Special:FilePath/Bj%C3%B6rk.jpg",
+		"https://example.com/photo.png":                                "https://example.com/photo.png",
+		"":                                                             "",
+	}
+	for in, want := range cases {
+		if got := imageURL(in); got != want {
+			t.Fatalf("imageURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
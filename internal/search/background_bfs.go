@@ -5,18 +5,46 @@ import (
 )
 
 // RunBackgroundBFS executes a search in the background and updates a Job.
-func RunBackgroundBFS(job *jobs.Job, req SearchRequest) {
+func (e *Engine) RunBackgroundBFS(job *jobs.Job, req SearchRequest) {
 	jobs.Manager.Update(job.ID, func(j *jobs.Job) {
 		j.Status = jobs.StatusRunning
 	})
 
-	hops, stepsList, msg, status, err := SearchArtists(
-		req.Start,
-		req.Target,
-		req.Depth,
-		3000,
-		false,
+	// read before searching, so a rebuild mid-search is not cached as current
+	version := e.cacheVersion()
+
+	var (
+		hops      int
+		stepsList []Step
+		msg       string
+		status    int
+		err       error
 	)
+	start, target := req.Endpoints()
+	switch req.Mode {
+	case ModeWidest:
+		hops, stepsList, msg, status, err = e.SearchWidest(
+			start,
+			target,
+			req.Depth,
+			widestNeighborLimit,
+		)
+	case ModeWeighted:
+		hops, stepsList, msg, status, err = e.SearchWeighted(
+			start,
+			target,
+			req.Depth,
+			weightedNeighborLimit,
+			req.Strategy,
+		)
+	default:
+		hops, stepsList, msg, status, err = e.SearchArtists(
+			start,
+			target,
+			req.Depth,
+			3000,
+		)
+	}
 
 	// stepsList IS ALREADY []Step
 	resp := SearchResponse{
@@ -40,41 +68,6 @@ func RunBackgroundBFS(job *jobs.Job, req SearchRequest) {
 		j.Status = jobs.StatusFinished
 		j.Result = resp
 	})
-}
-
-// ConvertSteps adapts the anonymous step type returned by SearchArtists
-// into the stable Step type used by the HTTP + jobs layers.
-func ConvertSteps(in []struct {
-	From   string
-	Tracks []struct {
-		ID            string
-		Name          string
-		RecordingID   string
-		RecordingName string
-		PhotoURL      string
-	}
-	To string
-}) []Step {
-	out := make([]Step, 0, len(in))
-
-	for _, s := range in {
-		step := Step{
-			From: s.From,
-			To:   s.To,
-		}
-
-		for _, t := range s.Tracks {
-			step.Tracks = append(step.Tracks, TrackInfo{
-				ID:            t.ID,
-				Name:          t.Name,
-				RecordingID:   t.RecordingID,
-				RecordingName: t.RecordingName,
-				PhotoURL:      t.PhotoURL,
-			})
-		}
-
-		out = append(out, step)
-	}
 
-	return out
+	e.storeCachedPath(req, version, resp)
 }
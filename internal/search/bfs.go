@@ -1,7 +1,7 @@
 package search
 
 import (
-	"fmt"
+	"context"
 	"log"
 	"strings"
 	"time"
@@ -15,35 +15,35 @@ import (
 // ============================================================
 //
 
-func RunSearchOptsBFS(
+const (
+	// bfsBatchSize is how many frontier artists share one NeighborsBatch call.
+	bfsBatchSize = 50
+	// bfsLookupTracks caps the evidence kept per /lookup neighbor.
+	bfsLookupTracks = 3
+)
+
+// RunSearchOptsBFS finds a shortest path from start to target over the
+// engine's graph, expanding the frontier a level at a time in batches.
+// Only the path is returned; hop evidence is loaded by the caller.
+func (e *Engine) RunSearchOptsBFS(
+	ctx context.Context,
 	start, target *sixdegrees.Artists,
 	maxDepth int,
 	verbose bool,
 	limit *int,
-	offline bool,
-) (*sixdegrees.Helper, []string, []string, [][]sixdegrees.Track, int, bool) {
-
-	s, err := Open("")
-	if err != nil {
-		log.Printf("RunSearchOptsBFS: failed to open DB: %v", err)
-		return nil, nil, nil, nil, 500, false
-	}
-	defer s.Close()
+) (*sixdegrees.Helper, []string, []string, int, bool) {
 
 	if start == nil || start.ID == "" || target == nil || target.ID == "" {
-		return nil, nil, nil, nil, 400, false
+		return nil, nil, nil, 400, false
 	}
 
+	h := sixdegrees.NewHelper()
+	h.ArtistByID[start.ID] = start
+	h.IDByName[start.Name] = start.ID
+
 	// trivial case
 	if start.ID == target.ID {
-		h := sixdegrees.NewHelper()
-		h.ArtistByID[start.ID] = start
-		h.IDByName[start.Name] = start.ID
-		return h,
-			[]string{start.Name},
-			[]string{start.ID},
-			[][]sixdegrees.Track{},
-			200, true
+		return h, []string{start.Name}, []string{start.ID}, 200, true
 	}
 
 	// neighbor cap per artist
@@ -58,202 +58,132 @@ func RunSearchOptsBFS(
 	const maxSearchDuration = 3000 * time.Second
 	startTime := time.Now()
 
-	h := sixdegrees.NewHelper()
-	h.ArtistByID[start.ID] = start
-	h.IDByName[start.Name] = start.ID
-
-	type queueItem struct {
-		A     *sixdegrees.Artists
-		Depth int
-	}
-
-	queue := []queueItem{{A: start, Depth: 0}}
-	visited := map[string]bool{start.ID: true}
-
 	prev := make(map[string]string)
-	prevTracks := make(map[string][]sixdegrees.Track)
-
-	var (
-		foundTarget     bool
-		finalPathIDs    []string
-		finalPathNames  []string
-		finalPathTracks [][]sixdegrees.Track
-	)
-	var expandedCount = 0
+	visited := map[string]bool{start.ID: true}
+	frontier := []*sixdegrees.Artists{start}
 
 	log.Println()
-	for len(queue) > 0 {
-		expandedCount = 0
-		// pop
-		item := queue[0]
-		queue = queue[1:]
-		SearchTicker.Artist = item.A.Name
-		// Only update if it goes up, do not revert down
-		if item.Depth > SearchTicker.Depth {
-			SearchTicker.Depth = item.Depth
-		}
-
-		// timeout
-		if time.Since(startTime) > maxSearchDuration {
-			return h, nil, nil, nil, 504, false
-		}
-
+	for depth := 0; len(frontier) > 0; depth++ {
 		// depth guard
-		if maxDepth > 0 && item.Depth > maxDepth {
-			continue
-		}
-
-		if verbose {
-			log.Printf("[BFS] Expanding %s at depth %d", item.A.Name, item.Depth)
-		}
-
-		neighbors, status, err := s.MusicBrainzNeighborProvider(
-			item.A,
-			perArtistLimit,
-			offline,
-		)
-		if status == 429 {
-			return h, nil, nil, nil, 429, false
-		}
-		if err != nil {
-			if verbose {
-				log.Printf("[BFS] error from provider for %s: %v", item.A.Name, err)
-			}
-			continue
+		if maxDepth > 0 && depth > maxDepth {
+			break
 		}
-
-		if verbose {
-			log.Printf("[BFS] Found %d neighbors for %s", len(neighbors), item.A.Name)
+		// Only update if it goes up, do not revert down
+		if depth > SearchTicker.Depth {
+			SearchTicker.Depth = depth
 		}
 
-		// ============================
-		// Build FrontendStep for /lookup
-		// ============================
-		step := FrontendStep{
-			ID:   item.A.ID,
-			Name: item.A.Name,
-			Neighbors: make([]struct {
-				ID     string      `json:"ID"`
-				Name   string      `json:"Name"`
-				Tracks []TrackInfo `json:"Tracks"`
-			}, 0, len(neighbors)),
-		}
+		var next []*sixdegrees.Artists
 
-		SearchTicker.Max = len(neighbors)
-		for _, nb := range neighbors {
-			if nb == nil || nb.Artist == nil || nb.Artist.ID == "" {
-				continue
+		for i := 0; i < len(frontier); i += bfsBatchSize {
+			// timeout
+			if time.Since(startTime) > maxSearchDuration {
+				return h, nil, nil, 504, false
 			}
 
-			expandedCount++
-			SearchTicker.Count = expandedCount
-
-			// Convert ArtistsWrapper → sixdegrees.Artists
-			convertedArtist := convertToArtist(nb.Artist)
-
-			// Convert TrackWrapper → []sixdegrees.Track
-			tracks := convertTrackList(nb.Track)
-
-			childID := convertedArtist.ID
-
-			// keep helper maps populated
-			if _, ok := h.ArtistByID[childID]; !ok {
-				h.ArtistByID[childID] = convertedArtist
-			}
-			if convertedArtist.Name != "" {
-				h.IDByName[convertedArtist.Name] = childID
+			batch := frontier[i:min(i+bfsBatchSize, len(frontier))]
+			ids := make([]string, len(batch))
+			for j, a := range batch {
+				ids[j] = a.ID
 			}
 
-			// Deduplicate within this edge
-			tracks = sixdegrees.DeduplicateTracks(tracks, 0.65, false)
-
-			// convert deduped tracks → TrackInfo for /lookup
-			ti := make([]TrackInfo, 0, len(tracks))
-			for _, t := range tracks {
-				ti = append(ti, TrackInfo{
-					ID:            t.ID,
-					Name:          t.Name,
-					RecordingID:   t.RecordingID,
-					RecordingName: t.RecordingName,
-					PhotoURL:      t.PhotoURL,
-				})
+			// a failed batch would hide part of the graph; report it
+			// rather than answer "no path"
+			neighbors, err := e.Graph.NeighborsBatch(ctx, ids, perArtistLimit)
+			if err != nil {
+				log.Printf("[BFS] error from graph for %d artists at depth %d: %v", len(ids), depth, err)
+				return h, nil, nil, 502, false
 			}
 
-			// append neighbor entry for /lookup
-			step.Neighbors = append(step.Neighbors, struct {
-				ID     string      `json:"ID"`
-				Name   string      `json:"Name"`
-				Tracks []TrackInfo `json:"Tracks"`
-			}{
-				ID:     nb.Artist.ID,
-				Name:   nb.Artist.Name,
-				Tracks: ti,
-			})
-
-			if len(tracks) == 0 {
-				continue
-			}
+			for _, a := range batch {
+				nbs := neighbors[a.ID]
+				SearchTicker.Artist = a.Name
+				SearchTicker.Max = len(nbs)
 
-			edgeKey := item.A.ID + "->" + childID
+				if verbose {
+					log.Printf("[BFS] Found %d neighbors for %s at depth %d", len(nbs), a.Name, depth)
+				}
 
-			// first time we see this child
-			if _, seen := prev[childID]; !seen {
-				prev[childID] = item.A.ID
-				prevTracks[edgeKey] = tracks
-				visited[childID] = true
+				// evidence for the hover panels, one lookup per expanded artist
+				refs := make([]ArtistRef, 0, len(nbs))
+				for _, nb := range nbs {
+					if nb.MBID != "" {
+						refs = append(refs, nb.ArtistRef)
+					}
+				}
+				evidence, err := EdgeEvidenceFor(ctx, e.Graph, ArtistRef{MBID: a.ID, Name: a.Name}, refs, bfsLookupTracks)
+				if err != nil {
+					log.Printf("[BFS] evidence failed for %s: %v", a.Name, err)
+				}
 
-				queue = append(queue, queueItem{
-					A:     convertedArtist,
-					Depth: item.Depth + 1,
-				})
-			}
+				// ============================
+				// Build FrontendStep for /lookup
+				// ============================
+				step := FrontendStep{
+					ID:   a.ID,
+					Name: a.Name,
+					Neighbors: make([]struct {
+						ID     string      `json:"ID"`
+						Name   string      `json:"Name"`
+						Tracks []TrackInfo `json:"Tracks"`
+					}, 0, len(nbs)),
+				}
 
-			// Hit target
-			if childID == target.ID {
-				foundTarget = true
+				for n, nb := range nbs {
+					SearchTicker.Count = n + 1
+					if nb.MBID == "" {
+						continue
+					}
 
-				finalPathIDs = reconstructIDPath(prev, start.ID, target.ID)
+					step.Neighbors = append(step.Neighbors, struct {
+						ID     string      `json:"ID"`
+						Name   string      `json:"Name"`
+						Tracks []TrackInfo `json:"Tracks"`
+					}{
+						ID:     nb.MBID,
+						Name:   nb.Name,
+						Tracks: append([]TrackInfo{}, evidence[nb.MBID]...),
+					})
+
+					// first time we see this artist
+					if visited[nb.MBID] {
+						continue
+					}
+					visited[nb.MBID] = true
+					prev[nb.MBID] = a.ID
+
+					child := &sixdegrees.Artists{
+						ID:     nb.MBID,
+						Name:   nb.Name,
+						Tracks: []sixdegrees.Track{},
+						Genres: map[string]int{},
+					}
+					h.ArtistByID[child.ID] = child
+					if child.Name != "" {
+						h.IDByName[child.Name] = child.ID
+					}
 
-				// names
-				finalPathNames = make([]string, 0, len(finalPathIDs))
-				for _, id := range finalPathIDs {
-					if art, ok := h.ArtistByID[id]; ok {
-						finalPathNames = append(finalPathNames, art.Name)
-					} else {
-						finalPathNames = append(finalPathNames, id)
+					// Hit target
+					if child.ID == target.ID {
+						GlobalNeighborLookup[strings.ToLower(a.Name)] = step
+						ids := reconstructIDPath(prev, start.ID, target.ID)
+						return h, pathNames(h, ids), ids, 200, true
 					}
-				}
 
-				// tracks per hop
-				finalPathTracks = make([][]sixdegrees.Track, 0, len(finalPathIDs)-1)
-				for i := 1; i < len(finalPathIDs); i++ {
-					from := finalPathIDs[i-1]
-					to := finalPathIDs[i]
-					eKey := from + "->" + to
-					finalPathTracks = append(finalPathTracks, prevTracks[eKey])
+					next = append(next, child)
 				}
 
-				queue = nil
-				break
+				// store the neighbors for this artist into the global lookup
+				if len(step.Neighbors) > 0 {
+					GlobalNeighborLookup[strings.ToLower(a.Name)] = step
+				}
 			}
 		}
 
-		// store the neighbors for this artist into the global lookup
-		if len(step.Neighbors) > 0 {
-			key := strings.ToLower(item.A.Name)
-			GlobalNeighborLookup[key] = step
-		}
-
-		if foundTarget {
-			break
-		}
-	}
-
-	if !foundTarget {
-		return h, nil, nil, nil, 404, false
+		frontier = next
 	}
 
-	return h, finalPathNames, finalPathIDs, finalPathTracks, 200, true
+	return h, nil, nil, 404, false
 }
 
 //
@@ -262,24 +192,17 @@ func RunSearchOptsBFS(
 // ============================================================
 //
 
-func convertToArtist(a *ArtistsWrapper) *sixdegrees.Artists {
-	if a == nil {
-		return nil
-	}
-	return &sixdegrees.Artists{
-		ID:     a.ID,
-		Name:   a.Name,
-		Tracks: []sixdegrees.Track{},
-		Genres: map[string]int{},
-	}
-}
-
-func debugTrackNames(tracks []sixdegrees.Track) string {
-	parts := make([]string, 0, len(tracks))
-	for _, t := range tracks {
-		parts = append(parts, fmt.Sprintf("%s (%s)", t.Name, t.RecordingID))
+// pathNames maps path IDs to artist names, falling back to the ID.
+func pathNames(h *sixdegrees.Helper, ids []string) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if art, ok := h.ArtistByID[id]; ok {
+			out = append(out, art.Name)
+		} else {
+			out = append(out, id)
+		}
 	}
-	return strings.Join(parts, " | ")
+	return out
 }
 
 func reconstructIDPath(prev map[string]string, startID, targetID string) []string {
@@ -299,16 +222,76 @@ func reconstructIDPath(prev map[string]string, startID, targetID string) []strin
 	return path
 }
 
-func convertTrackList(in []TrackWrapper) []sixdegrees.Track {
+func ConvertTrackList(in []TrackWrapper) []sixdegrees.Track {
 	out := make([]sixdegrees.Track, 0, len(in))
 	for _, t := range in {
+		credits := make([]sixdegrees.CreditedName, 0, len(t.Credits))
+		for _, c := range t.Credits {
+			credits = append(credits, sixdegrees.CreditedName{
+				ArtistID:   c.ArtistID,
+				ArtistName: c.ArtistName,
+				CreditedAs: c.CreditedAs,
+			})
+		}
 		out = append(out, sixdegrees.Track{
-			ID:            t.ID,
-			Name:          t.Name,
-			RecordingID:   t.RecordingID,
-			RecordingName: t.RecordingName,
-			PhotoURL:      t.PhotoURL,
+			ID:             t.ID,
+			Name:           t.Name,
+			RecordingID:    t.RecordingID,
+			RecordingName:  t.RecordingName,
+			PhotoURL:       t.PhotoURL,
+			ReleaseTitle:   t.ReleaseTitle,
+			ReleaseGroupID: t.ReleaseGroupID,
+			ReleaseYear:    t.ReleaseYear,
+			ReleaseType:    t.ReleaseType,
+			Credits:        credits,
 		})
 	}
 	return out
 }
+
+// TrackInfoFromTrack converts a path track back into its API form,
+// carrying the release evidence along with it.
+func TrackInfoFromTrack(t sixdegrees.Track) TrackInfo {
+	ti := TrackInfo{
+		ID:             t.ID,
+		Name:           t.Name,
+		RecordingID:    t.RecordingID,
+		RecordingName:  t.RecordingName,
+		PhotoURL:       t.PhotoURL,
+		ReleaseTitle:   t.ReleaseTitle,
+		ReleaseGroupID: t.ReleaseGroupID,
+		ReleaseYear:    t.ReleaseYear,
+		ReleaseType:    t.ReleaseType,
+	}
+	for _, c := range t.Credits {
+		ti.Credits = append(ti.Credits, CreditInfo{
+			ArtistID:   c.ArtistID,
+			ArtistName: c.ArtistName,
+			CreditedAs: c.CreditedAs,
+		})
+	}
+	return ti
+}
+
+// TrackFromInfo is the inverse of TrackInfoFromTrack.
+func TrackFromInfo(ti TrackInfo) sixdegrees.Track {
+	t := sixdegrees.Track{
+		ID:             ti.ID,
+		Name:           ti.Name,
+		RecordingID:    ti.RecordingID,
+		RecordingName:  ti.RecordingName,
+		PhotoURL:       ti.PhotoURL,
+		ReleaseTitle:   ti.ReleaseTitle,
+		ReleaseGroupID: ti.ReleaseGroupID,
+		ReleaseYear:    ti.ReleaseYear,
+		ReleaseType:    ti.ReleaseType,
+	}
+	for _, c := range ti.Credits {
+		t.Credits = append(t.Credits, sixdegrees.CreditedName{
+			ArtistID:   c.ArtistID,
+			ArtistName: c.ArtistName,
+			CreditedAs: c.CreditedAs,
+		})
+	}
+	return t
+}
@@ -0,0 +1,138 @@
+package search
+
+import (
+	"context"
+	"fmt"
+
+	sixdegrees "github.com/Jonnymurillo288/MelodyMap/sixDegrees"
+)
+
+//
+// ========================================================================
+// Artist centrality (written by cmd/centrality)
+// ========================================================================
+//
+
+// Centrality is the persisted graph-importance scores of one artist.
+type Centrality struct {
+	PageRank    float64 `json:"pagerank"`
+	Degree      int     `json:"degree"`
+	Betweenness float64 `json:"betweenness"`
+	Popularity  float64 `json:"popularity"` // PageRank percentile, 0..100
+}
+
+// SaveCentrality replaces all centrality scores in one transaction and
+// derives the PageRank percentile used as a popularity stand-in.
+func (s *Store) SaveCentrality(
+	ctx context.Context,
+	artistIDs []int32,
+	pagerank []float64,
+	degree []int32,
+	betweenness []float64,
+) error {
+
+	n := len(artistIDs)
+	if len(pagerank) != n || len(degree) != n || len(betweenness) != n {
+		return fmt.Errorf("centrality length mismatch")
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `TRUNCATE artist_centrality;`); err != nil {
+		return err
+	}
+
+	for i := 0; i < n; i += analyticsWriteBatch {
+		end := min(i+analyticsWriteBatch, n)
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO artist_centrality (artist_id, pagerank, degree, betweenness)
+			SELECT * FROM unnest($1::int[], $2::float8[], $3::int[], $4::float8[]);
+		`, artistIDs[i:end], pagerank[i:end], degree[i:end], betweenness[i:end])
+		if err != nil {
+			return err
+		}
+	}
+
+	pct := `
+		UPDATE artist_centrality c
+		SET popularity = p.pct
+		FROM (
+			SELECT artist_id, percent_rank() OVER (ORDER BY pagerank) * 100 AS pct
+			FROM artist_centrality
+		) p
+		WHERE p.artist_id = c.artist_id;
+	`
+	if _, err := tx.ExecContext(ctx, pct); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetCentrality returns the stored scores for one artist.
+func (s *Store) GetCentrality(ctx context.Context, mbid string) (*Centrality, error) {
+	q := `
+		SELECT c.pagerank, c.degree, c.betweenness, c.popularity
+		FROM artist_centrality c
+		JOIN artist a ON a.id = c.artist_id
+		WHERE c.artist_id = ` + artistIDByGID("$1") + `;
+	`
+	var c Centrality
+	err := s.DB.QueryRowContext(ctx, q, mbid).Scan(&c.PageRank, &c.Degree, &c.Betweenness, &c.Popularity)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// FillPopularity sets Popularity from the PageRank percentile for artists
+// that have none (MusicBrainz-only artists with no Spotify popularity), so
+// they can be weighted by PopularityDiffStrategy.
+func (s *Store) FillPopularity(ctx context.Context, artists []*sixdegrees.Artists) error {
+	var mbids []string
+	for _, a := range artists {
+		if a != nil && a.ID != "" && a.Popularity == 0 {
+			mbids = append(mbids, a.ID)
+		}
+	}
+	if len(mbids) == 0 {
+		return nil
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT a.gid::text, c.popularity
+		FROM artist_centrality c
+		JOIN artist a ON a.id = c.artist_id
+		WHERE a.gid = ANY($1::uuid[]);
+	`, mbids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	pop := make(map[string]float64, len(mbids))
+	for rows.Next() {
+		var mbid string
+		var p float64
+		if err := rows.Scan(&mbid, &p); err != nil {
+			return err
+		}
+		pop[mbid] = p
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, a := range artists {
+		if a != nil && a.Popularity == 0 {
+			if p, ok := pop[a.ID]; ok {
+				a.Popularity = p
+			}
+		}
+	}
+	return nil
+}
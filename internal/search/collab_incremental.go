@@ -0,0 +1,410 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+//
+// ========================================================================
+// Incremental artist_collab maintenance
+// ========================================================================
+//
+// Migrate rebuilds artist_collab from scratch. UpdateCollab instead
+// re-derives only the recordings touched since the previous run:
+//
+//   - recordings, tracks, media and releases whose last_updated moved,
+//     new artist credits and edited or merged artists;
+//   - recordings and tracks that were deleted, and release groups whose
+//     first release year or compilation type changed. Those rows carry no
+//     timestamp, so triggers log them to collab_dirty_recording and
+//     collab_dirty_release_group, and each run consumes the log.
+//
+// Their artist_collab rows are deleted and reinserted, and every summary
+// pair they feed and the degree of every artist in those pairs are
+// recomputed.
+//
+// The watermark is kept in melodymap_meta. On a replicated mirror the
+// timestamps above are the master's, so the watermark is the master time
+// of the last applied packet (replication_control.last_replication_date);
+// elsewhere it is the local time. Either way it trails by
+// collabWatermarkSlack so rows committed while their timestamp aged are
+// seen again next time; reprocessing a recording is idempotent.
+
+const (
+	metaCollabUpdatedThrough = "collab_updated_through" // unix microseconds
+	metaCollabReplicationSeq = "collab_replication_sequence"
+
+	collabWatermarkSlack = 10 * time.Minute
+)
+
+// CollabUpdate reports one incremental run.
+type CollabUpdate struct {
+	Since               time.Time `json:"since"`
+	Through             time.Time `json:"through"`
+	Recordings          int64     `json:"recordings"` // changed or deleted recordings re-derived
+	Pairs               int64     `json:"pairs"`      // summary pairs recomputed
+	ReplicationSequence int64     `json:"replicationSequence,omitempty"`
+	DataVersion         int64     `json:"dataVersion"` // bumped only when something changed
+}
+
+// collabClock is where the MusicBrainz tables stand: the time changes are
+// complete up to and, on a replicated mirror, the packet applied last.
+type collabClock struct {
+	now        time.Time
+	replicated bool
+	sequence   int64
+}
+
+// UpdateCollab brings artist_collab and artist_collab_summary up to date
+// with the MusicBrainz tables, touching only what changed since the last
+// run or the last Migrate. When anything changed it bumps the data
+// version, which retires cached paths, in the same transaction.
+func (s *Store) UpdateCollab(ctx context.Context) (*CollabUpdate, error) {
+	tx, err := s.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	sinceUS, ok, err := getMeta(ctx, tx, metaCollabUpdatedThrough)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("no collab watermark; run Migrate once first")
+	}
+	up := &CollabUpdate{Since: time.UnixMicro(sinceUS).UTC()}
+
+	clock, err := readCollabClock(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	up.ReplicationSequence = clock.sequence
+
+	// a mirror only changes when a packet is applied
+	if clock.replicated {
+		seq, ok, err := getMeta(ctx, tx, metaCollabReplicationSeq)
+		if err != nil {
+			return nil, err
+		}
+		if ok && seq == clock.sequence {
+			up.Through = up.Since
+			up.DataVersion, _, err = getMeta(ctx, tx, "data_version")
+			return up, err
+		}
+	}
+
+	steps := []struct {
+		name string
+		q    string
+		args []any
+	}{
+		{"changed recordings", `
+			CREATE TEMP TABLE collab_changed ON COMMIT DROP AS
+			SELECT r.id AS recording_id
+			FROM recording r
+			WHERE r.last_updated > $1
+			UNION
+			SELECT r.id
+			FROM artist_credit ac
+			JOIN recording r ON r.artist_credit = ac.id
+			WHERE ac.created > $1
+			UNION
+			SELECT r.id
+			FROM artist a
+			JOIN artist_credit_name acn ON acn.artist = a.id
+			JOIN recording r            ON r.artist_credit = acn.artist_credit
+			WHERE a.last_updated > $1
+			UNION
+			SELECT t.recording
+			FROM track t
+			WHERE t.last_updated > $1
+			UNION
+			SELECT t.recording
+			FROM medium m
+			JOIN track t ON t.medium = m.id
+			WHERE m.last_updated > $1
+			UNION
+			SELECT t.recording
+			FROM release rl
+			JOIN medium m ON m.release = rl.id
+			JOIN track t  ON t.medium = m.id
+			WHERE rl.last_updated > $1
+			UNION
+			SELECT t.recording
+			FROM collab_dirty_release_group d
+			JOIN release rl ON rl.release_group = d.release_group_id
+			JOIN medium m   ON m.release = rl.id
+			JOIN track t    ON t.medium = m.id
+			UNION
+			SELECT recording_id
+			FROM collab_dirty_recording;
+		`, []any{up.Since}},
+		// only rows this snapshot saw; later ones wait for the next run
+		{"consume log", `
+			DELETE FROM collab_dirty_recording;
+			DELETE FROM collab_dirty_release_group;
+		`, nil},
+		{"index changed", `
+			CREATE INDEX ON collab_changed (recording_id);
+		`, nil},
+		{"affected pairs", `
+			CREATE TEMP TABLE collab_affected (
+				artist_id INT NOT NULL,
+				neighbor_artist_id INT NOT NULL,
+				PRIMARY KEY (artist_id, neighbor_artist_id)
+			) ON COMMIT DROP;
+		`, nil},
+		{"old pairs", `
+			INSERT INTO collab_affected
+			SELECT DISTINCT c.artist_id, c.neighbor_artist_id
+			FROM artist_collab c
+			JOIN collab_changed ch ON ch.recording_id = c.recording_id
+			ON CONFLICT DO NOTHING;
+		`, nil},
+		{"delete collabs", `
+			DELETE FROM artist_collab c
+			USING collab_changed ch
+			WHERE c.recording_id = ch.recording_id;
+		`, nil},
+		// the same pairs Migrate derives, for the changed recordings only
+		{"insert collabs", `
+			INSERT INTO artist_collab (artist_id, neighbor_artist_id, recording_id)
+			SELECT
+				acn1.artist,
+				acn2.artist,
+				r.id
+			FROM collab_changed ch
+			JOIN recording r ON r.id = ch.recording_id
+			JOIN artist_credit ac ON ac.id = r.artist_credit
+			JOIN artist_credit_name acn1 ON acn1.artist_credit = ac.id
+			JOIN artist_credit_name acn2 ON acn2.artist_credit = ac.id
+			WHERE acn1.artist <> acn2.artist
+			ON CONFLICT DO NOTHING;
+		`, nil},
+		{"new pairs", `
+			INSERT INTO collab_affected
+			SELECT DISTINCT c.artist_id, c.neighbor_artist_id
+			FROM artist_collab c
+			JOIN collab_changed ch ON ch.recording_id = c.recording_id
+			ON CONFLICT DO NOTHING;
+		`, nil},
+		{"delete summaries", `
+			DELETE FROM artist_collab_summary cs
+			USING collab_affected p
+			WHERE cs.artist_id = p.artist_id
+			  AND cs.neighbor_artist_id = p.neighbor_artist_id;
+		`, nil},
+		{"insert summaries", collabSummaryInsert(`
+			JOIN collab_affected p
+			  ON p.artist_id = c.artist_id
+			 AND p.neighbor_artist_id = c.neighbor_artist_id
+		`), nil},
+		{"degrees", `
+			INSERT INTO artist_collab_degree (artist_id, degree)
+			SELECT a.artist_id, (
+				SELECT count(*) FROM artist_collab_summary cs WHERE cs.artist_id = a.artist_id
+			)
+			FROM (
+				SELECT artist_id FROM collab_affected
+				UNION
+				SELECT neighbor_artist_id FROM collab_affected
+			) a
+			ON CONFLICT (artist_id) DO UPDATE SET degree = EXCLUDED.degree;
+		`, nil},
+	}
+	for _, st := range steps {
+		if _, err := tx.ExecContext(ctx, st.q, st.args...); err != nil {
+			return nil, fmt.Errorf("%s: %w", st.name, err)
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT
+			(SELECT count(*) FROM collab_changed),
+			(SELECT count(*) FROM collab_affected);
+	`).Scan(&up.Recordings, &up.Pairs)
+	if err != nil {
+		return nil, err
+	}
+
+	up.Through, err = setCollabWatermark(ctx, tx, clock, up.Since)
+	if err != nil {
+		return nil, err
+	}
+
+	if up.Recordings == 0 {
+		up.DataVersion, _, err = getMeta(ctx, tx, "data_version")
+	} else {
+		up.DataVersion, err = bumpDataVersion(ctx, tx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return up, tx.Commit()
+}
+
+// migrateCollabIncremental indexes the change columns UpdateCollab scans,
+// installs the triggers that log what has no timestamp and records the
+// watermark. Migrate runs it before rebuilding artist_collab, so anything
+// that changes while the rebuild runs is picked up by the next
+// UpdateCollab. The triggers fire ALWAYS so replication applies them too.
+func (s *Store) migrateCollabIncremental(ctx context.Context) error {
+	q := `
+		CREATE INDEX IF NOT EXISTS recording_last_updated_idx ON recording (last_updated);
+		CREATE INDEX IF NOT EXISTS artist_last_updated_idx ON artist (last_updated);
+		CREATE INDEX IF NOT EXISTS artist_credit_created_idx ON artist_credit (created);
+		CREATE INDEX IF NOT EXISTS track_last_updated_idx ON track (last_updated);
+		CREATE INDEX IF NOT EXISTS medium_last_updated_idx ON medium (last_updated);
+		CREATE INDEX IF NOT EXISTS release_last_updated_idx ON release (last_updated);
+
+		CREATE TABLE IF NOT EXISTS collab_dirty_recording (
+			recording_id INT NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS collab_dirty_release_group (
+			release_group_id INT NOT NULL
+		);
+
+		CREATE OR REPLACE FUNCTION collab_log_recording() RETURNS trigger AS $$
+		BEGIN
+			IF TG_TABLE_NAME = 'recording' THEN
+				INSERT INTO collab_dirty_recording VALUES (OLD.id);
+			ELSIF TG_OP = 'DELETE' THEN
+				INSERT INTO collab_dirty_recording VALUES (OLD.recording);
+			ELSIF OLD.recording <> NEW.recording THEN
+				INSERT INTO collab_dirty_recording VALUES (OLD.recording);
+			END IF;
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql;
+
+		CREATE OR REPLACE FUNCTION collab_log_release_group() RETURNS trigger AS $$
+		BEGIN
+			IF TG_TABLE_NAME = 'release_group_meta' THEN
+				IF OLD.first_release_date_year IS DISTINCT FROM NEW.first_release_date_year THEN
+					INSERT INTO collab_dirty_release_group VALUES (NEW.id);
+				END IF;
+			ELSIF TG_OP = 'DELETE' THEN
+				INSERT INTO collab_dirty_release_group VALUES (OLD.release_group);
+			ELSE
+				INSERT INTO collab_dirty_release_group VALUES (NEW.release_group);
+			END IF;
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql;
+
+		DROP TRIGGER IF EXISTS collab_recording_deleted ON recording;
+		CREATE TRIGGER collab_recording_deleted AFTER DELETE ON recording
+			FOR EACH ROW EXECUTE FUNCTION collab_log_recording();
+		ALTER TABLE recording ENABLE ALWAYS TRIGGER collab_recording_deleted;
+
+		DROP TRIGGER IF EXISTS collab_track_moved ON track;
+		CREATE TRIGGER collab_track_moved AFTER UPDATE OF recording OR DELETE ON track
+			FOR EACH ROW EXECUTE FUNCTION collab_log_recording();
+		ALTER TABLE track ENABLE ALWAYS TRIGGER collab_track_moved;
+
+		DROP TRIGGER IF EXISTS collab_release_group_year ON release_group_meta;
+		CREATE TRIGGER collab_release_group_year AFTER UPDATE ON release_group_meta
+			FOR EACH ROW EXECUTE FUNCTION collab_log_release_group();
+		ALTER TABLE release_group_meta ENABLE ALWAYS TRIGGER collab_release_group_year;
+
+		DROP TRIGGER IF EXISTS collab_release_group_type ON release_group_secondary_type_join;
+		CREATE TRIGGER collab_release_group_type AFTER INSERT OR DELETE ON release_group_secondary_type_join
+			FOR EACH ROW EXECUTE FUNCTION collab_log_release_group();
+		ALTER TABLE release_group_secondary_type_join ENABLE ALWAYS TRIGGER collab_release_group_type;
+
+		-- the rebuild that follows covers everything logged so far
+		TRUNCATE collab_dirty_recording, collab_dirty_release_group;
+	`
+	if _, err := s.DB.ExecContext(ctx, q); err != nil {
+		return err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	clock, err := readCollabClock(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if _, err := setCollabWatermark(ctx, tx, clock, time.Time{}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// readCollabClock reads the replication state, if the database is a
+// replicated MusicBrainz mirror, and the time changes are complete up to.
+func readCollabClock(ctx context.Context, tx *sql.Tx) (collabClock, error) {
+	var c collabClock
+	err := tx.QueryRowContext(ctx, `
+		SELECT now(), to_regclass('replication_control') IS NOT NULL;
+	`).Scan(&c.now, &c.replicated)
+	if err != nil || !c.replicated {
+		return c, err
+	}
+
+	var seq sql.NullInt64
+	var applied sql.NullTime
+	err = tx.QueryRowContext(ctx, `
+		SELECT current_replication_sequence, last_replication_date
+		FROM replication_control;
+	`).Scan(&seq, &applied)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.replicated = false
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	if !seq.Valid || !applied.Valid {
+		c.replicated = false
+		return c, nil
+	}
+	c.sequence, c.now = seq.Int64, applied.Time
+	return c, nil
+}
+
+// setCollabWatermark stores the clock minus the slack, never earlier than
+// floor, and the replication sequence on a mirror.
+func setCollabWatermark(ctx context.Context, tx *sql.Tx, c collabClock, floor time.Time) (time.Time, error) {
+	through := c.now.Add(-collabWatermarkSlack)
+	if through.Before(floor) {
+		through = floor
+	}
+	if err := setMeta(ctx, tx, metaCollabUpdatedThrough, through.UnixMicro()); err != nil {
+		return time.Time{}, err
+	}
+	if c.replicated {
+		if err := setMeta(ctx, tx, metaCollabReplicationSeq, c.sequence); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return through.UTC(), nil
+}
+
+func getMeta(ctx context.Context, tx *sql.Tx, key string) (int64, bool, error) {
+	var v int64
+	err := tx.QueryRowContext(ctx, `
+		SELECT value FROM melodymap_meta WHERE key = $1;
+	`, key).Scan(&v)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	return v, err == nil, err
+}
+
+func setMeta(ctx context.Context, tx *sql.Tx, key string, value int64) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO melodymap_meta (key, value) VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value;
+	`, key, value)
+	return err
+}
@@ -0,0 +1,317 @@
+package search
+
+import (
+	"context"
+	"time"
+
+	sixdegrees "github.com/Jonnymurillo288/MelodyMap/sixDegrees"
+)
+
+//
+// ========================================================================
+// artist_collab_summary — one row per (artist, neighbor) pair
+// ========================================================================
+//
+
+// recencyHorizonYears is how far back a collaboration can be before its
+// RecencyScore bottoms out at zero.
+const recencyHorizonYears = 50
+
+// CollabSummary is the pre-aggregated view of every recording two
+// artists share, as stored in artist_collab_summary.
+type CollabSummary struct {
+	ArtistID         int
+	NeighborID       int
+	NeighborMBID     string
+	NeighborName     string
+	SharedRecordings int
+	SharedReleases   int
+	FirstYear        int // 0 when unknown
+	LastYear         int // 0 when unknown
+	CompilationOnly  bool
+}
+
+// EdgeContext converts the summary into the weighting context used by
+// the sixdegrees strategies.
+func (c CollabSummary) EdgeContext() sixdegrees.EdgeContext {
+	return sixdegrees.EdgeContext{
+		SharedCount:   c.SharedRecordings,
+		IsCompilation: c.CompilationOnly,
+		RecencyScore:  recencyScore(c.LastYear, time.Now().Year()),
+	}
+}
+
+func recencyScore(lastYear, nowYear int) float64 {
+	if lastYear <= 0 {
+		return 0
+	}
+	age := float64(nowYear - lastYear)
+	if age <= 0 {
+		return 1
+	}
+	if age >= recencyHorizonYears {
+		return 0
+	}
+	return 1 - age/recencyHorizonYears
+}
+
+func (s *Store) migrateCollabSummary(ctx context.Context) error {
+	q := `
+		CREATE TABLE IF NOT EXISTS artist_collab_summary (
+			artist_id INT NOT NULL,
+			neighbor_artist_id INT NOT NULL,
+			shared_recordings INT NOT NULL,
+			shared_releases INT NOT NULL,
+			first_year SMALLINT,
+			last_year SMALLINT,
+			compilation_only BOOLEAN NOT NULL DEFAULT FALSE,
+			CONSTRAINT artist_collab_summary_pk PRIMARY KEY (artist_id, neighbor_artist_id)
+		);
+
+		CREATE INDEX IF NOT EXISTS artist_collab_summary_neighbor_idx
+			ON artist_collab_summary (neighbor_artist_id);
+
+		CREATE INDEX IF NOT EXISTS artist_collab_summary_shared_idx
+			ON artist_collab_summary (artist_id, shared_recordings DESC);
+
+		-- distinct collaborators per artist, for ranking without a count
+		CREATE TABLE IF NOT EXISTS artist_collab_degree (
+			artist_id INT PRIMARY KEY,
+			degree INT NOT NULL
+		);
+	`
+
+	if _, err := s.DB.ExecContext(ctx, q); err != nil {
+		return err
+	}
+
+	// the upsert below never removes a pair, so drop the ones that no
+	// longer share a recording first
+	q = `
+		DELETE FROM artist_collab_summary cs
+		WHERE NOT EXISTS (
+			SELECT 1 FROM artist_collab c
+			WHERE c.artist_id = cs.artist_id
+			  AND c.neighbor_artist_id = cs.neighbor_artist_id
+		);
+	`
+	if _, err := s.DB.ExecContext(ctx, q); err != nil {
+		return err
+	}
+	if _, err := s.DB.ExecContext(ctx, collabSummaryInsert("")); err != nil {
+		return err
+	}
+
+	q = `
+		TRUNCATE artist_collab_degree;
+
+		INSERT INTO artist_collab_degree (artist_id, degree)
+		SELECT artist_id, count(*)
+		FROM artist_collab_summary
+		GROUP BY artist_id;
+	`
+	_, err := s.DB.ExecContext(ctx, q)
+	return err
+}
+
+// collabSummaryInsert aggregates artist_collab into artist_collab_summary.
+// filter is joined after artist_collab c to restrict which pairs are
+// recomputed; empty recomputes every pair.
+//
+// Recordings that never made it onto a release still count as shared
+// recordings; they just contribute no release, year or compilation info.
+func collabSummaryInsert(filter string) string {
+	return `
+		INSERT INTO artist_collab_summary (
+			artist_id, neighbor_artist_id,
+			shared_recordings, shared_releases,
+			first_year, last_year, compilation_only
+		)
+		SELECT
+			c.artist_id,
+			c.neighbor_artist_id,
+			count(DISTINCT c.recording_id),
+			count(DISTINCT rl.id),
+			min(rgm.first_release_date_year),
+			max(rgm.first_release_date_year),
+			COALESCE(bool_and(comp.release_group IS NOT NULL) FILTER (WHERE rl.id IS NOT NULL), FALSE)
+		FROM artist_collab c
+		` + filter + `
+		LEFT JOIN track t              ON t.recording = c.recording_id
+		LEFT JOIN medium m             ON m.id = t.medium
+		LEFT JOIN release rl           ON rl.id = m.release
+		LEFT JOIN release_group_meta rgm ON rgm.id = rl.release_group
+		LEFT JOIN (
+			SELECT DISTINCT j.release_group
+			FROM release_group_secondary_type_join j
+			JOIN release_group_secondary_type st ON st.id = j.secondary_type
+			WHERE st.name = 'Compilation'
+		) comp ON comp.release_group = rl.release_group
+		GROUP BY c.artist_id, c.neighbor_artist_id
+		ON CONFLICT (artist_id, neighbor_artist_id) DO UPDATE SET
+			shared_recordings = EXCLUDED.shared_recordings,
+			shared_releases   = EXCLUDED.shared_releases,
+			first_year        = EXCLUDED.first_year,
+			last_year         = EXCLUDED.last_year,
+			compilation_only  = EXCLUDED.compilation_only;
+	`
+}
+
+//
+// ========================================================================
+// Summary lookups
+// ========================================================================
+//
+
+const collabSummaryColumns = `
+	cs.artist_id,
+	cs.neighbor_artist_id,
+	a2.gid::text,
+	a2.name,
+	cs.shared_recordings,
+	cs.shared_releases,
+	COALESCE(cs.first_year, 0),
+	COALESCE(cs.last_year, 0),
+	cs.compilation_only
+`
+
+func scanCollabSummary(sc interface{ Scan(...any) error }) (CollabSummary, error) {
+	var c CollabSummary
+	err := sc.Scan(
+		&c.ArtistID,
+		&c.NeighborID,
+		&c.NeighborMBID,
+		&c.NeighborName,
+		&c.SharedRecordings,
+		&c.SharedReleases,
+		&c.FirstYear,
+		&c.LastYear,
+		&c.CompilationOnly,
+	)
+	return c, err
+}
+
+// GetCollabSummary returns the aggregated edge between two artists, or
+// sql.ErrNoRows if they never shared a recording.
+func (s *Store) GetCollabSummary(ctx context.Context, fromMBID, toMBID string) (*CollabSummary, error) {
+	q := `
+		SELECT ` + collabSummaryColumns + `
+		FROM artist_collab_summary cs
+		JOIN artist a1 ON a1.id = cs.artist_id
+		JOIN artist a2 ON a2.id = cs.neighbor_artist_id
+		WHERE cs.artist_id = ` + artistIDByGID("$1") + `
+		  AND cs.neighbor_artist_id = ` + artistIDByGID("$2") + `;
+	`
+	c, err := scanCollabSummary(s.DB.QueryRowContext(ctx, q, fromMBID, toMBID))
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ListCollabSummaries returns an artist's neighbors ordered by the number
+// of shared recordings, strongest first.
+func (s *Store) ListCollabSummaries(ctx context.Context, mbid string, limit int) ([]CollabSummary, error) {
+	if limit <= 0 {
+		limit = 200
+	}
+
+	q := `
+		SELECT ` + collabSummaryColumns + `
+		FROM artist_collab_summary cs
+		JOIN artist a1 ON a1.id = cs.artist_id
+		JOIN artist a2 ON a2.id = cs.neighbor_artist_id
+		WHERE cs.artist_id = ` + artistIDByGID("$1") + `
+		ORDER BY cs.shared_recordings DESC, cs.neighbor_artist_id
+		LIMIT $2;
+	`
+
+	rows, err := s.DB.QueryContext(ctx, q, mbid, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []CollabSummary
+	for rows.Next() {
+		c, err := scanCollabSummary(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// EdgeContexts returns the weighting context of each given edge, keyed
+// by MBID pair as given. Pairs that share no recording are left out.
+func (s *Store) EdgeContexts(ctx context.Context, edges []sixdegrees.EdgeKey) (map[sixdegrees.EdgeKey]sixdegrees.EdgeContext, error) {
+	out := make(map[sixdegrees.EdgeKey]sixdegrees.EdgeContext, len(edges))
+	if len(edges) == 0 {
+		return out, nil
+	}
+	from := make([]string, len(edges))
+	to := make([]string, len(edges))
+	for i, e := range edges {
+		from[i], to[i] = e.From, e.To
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT
+			e.from_gid::text,
+			e.to_gid::text,
+			cs.shared_recordings,
+			COALESCE(cs.last_year, 0),
+			cs.compilation_only
+		FROM unnest($1::uuid[], $2::uuid[]) AS e(from_gid, to_gid)
+		JOIN artist a1 ON a1.gid = e.from_gid
+		JOIN artist a2 ON a2.gid = e.to_gid
+		JOIN artist_collab_summary cs
+		  ON cs.artist_id = a1.id AND cs.neighbor_artist_id = a2.id;
+	`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var k sixdegrees.EdgeKey
+		var c CollabSummary
+		if err := rows.Scan(&k.From, &k.To, &c.SharedRecordings, &c.LastYear, &c.CompilationOnly); err != nil {
+			return nil, err
+		}
+		out[k] = c.EdgeContext()
+	}
+	return out, rows.Err()
+}
+
+// NeighborIDsBatch returns the neighbor ids of every artist in ids,
+// keyed by artist id. It works on internal ids only, which keeps wide
+// graph walks (distance profiles, analytics) cheap.
+func (s *Store) NeighborIDsBatch(ctx context.Context, ids []int) (map[int][]int, error) {
+	out := make(map[int][]int, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	q := `
+		SELECT artist_id, neighbor_artist_id
+		FROM artist_collab_summary
+		WHERE artist_id = ANY($1)
+		ORDER BY artist_id, shared_recordings DESC;
+	`
+	rows, err := s.DB.QueryContext(ctx, q, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a, nb int
+		if err := rows.Scan(&a, &nb); err != nil {
+			return nil, err
+		}
+		out[a] = append(out[a], nb)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,222 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/jobs"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	profileDefaultDepth    = 3
+	profileMaxDepth        = 6
+	profileMaxArtists      = 500000 // stop the walk once this many artists are reached
+	profileBatchSize       = 1000
+	profileExamplesPerRing = 5
+)
+
+// DistanceRing is every artist at exactly Depth hops from the profiled artist.
+type DistanceRing struct {
+	Depth    int         `json:"depth"`
+	Count    int         `json:"count"`
+	Examples []ArtistRef `json:"examples"`
+}
+
+// DistanceProfile summarizes how far the rest of the graph sits from one artist.
+type DistanceProfile struct {
+	MBID              string         `json:"mbid"`
+	Name              string         `json:"name"`
+	MaxDepth          int            `json:"maxDepth"`
+	Rings             []DistanceRing `json:"rings"`
+	Reached           int            `json:"reached"`
+	AverageSeparation float64        `json:"averageSeparation"`
+	Truncated         bool           `json:"truncated"` // hit profileMaxArtists before MaxDepth
+	ComputedAt        time.Time      `json:"computedAt"`
+}
+
+var (
+	distanceProfileCache, _ = lru.New(256)
+
+	profileJobsMu sync.Mutex
+	profileJobs   = make(map[string]string) // cache key -> in-flight job ID
+)
+
+func profileKey(mbid string, depth int) string {
+	return mbid + "|" + strconv.Itoa(depth)
+}
+
+// ClampProfileDepth applies the default and maximum profile depth.
+func ClampProfileDepth(depth int) int {
+	if depth <= 0 {
+		return profileDefaultDepth
+	}
+	if depth > profileMaxDepth {
+		return profileMaxDepth
+	}
+	return depth
+}
+
+// CachedDistanceProfile returns a previously computed profile, if any.
+func CachedDistanceProfile(mbid string, depth int) (*DistanceProfile, bool) {
+	v, ok := distanceProfileCache.Get(profileKey(mbid, depth))
+	if !ok {
+		return nil, false
+	}
+	return v.(*DistanceProfile), true
+}
+
+// StartDistanceProfile launches a background profile job, or returns the
+// job already computing the same profile.
+func (s *Store) StartDistanceProfile(mbid string, depth int) *jobs.Job {
+	key := profileKey(mbid, depth)
+
+	profileJobsMu.Lock()
+	defer profileJobsMu.Unlock()
+
+	if id, ok := profileJobs[key]; ok {
+		if j, ok := jobs.Manager.Get(id); ok {
+			return j
+		}
+	}
+
+	job := jobs.Manager.CreateJob(mbid, "")
+	profileJobs[key] = job.ID
+	go s.RunDistanceProfile(job, mbid, depth)
+	return job
+}
+
+// RunDistanceProfile computes a profile in the background and updates a Job.
+func (s *Store) RunDistanceProfile(job *jobs.Job, mbid string, depth int) {
+	key := profileKey(mbid, depth)
+	defer func() {
+		profileJobsMu.Lock()
+		delete(profileJobs, key)
+		profileJobsMu.Unlock()
+	}()
+
+	jobs.Manager.Update(job.ID, func(j *jobs.Job) {
+		j.Status = jobs.StatusRunning
+	})
+
+	fail := func(msg string) {
+		jobs.Manager.Update(job.ID, func(j *jobs.Job) {
+			j.Status = jobs.StatusError
+			j.Error = msg
+		})
+	}
+
+	p, err := s.DistanceProfile(context.Background(), mbid, depth, func(progress float64, stage string) {
+		jobs.Manager.SetProgress(job.ID, progress, stage)
+	})
+	if err != nil {
+		fail(err.Error())
+		return
+	}
+
+	distanceProfileCache.Add(key, p)
+
+	jobs.Manager.Update(job.ID, func(j *jobs.Job) {
+		j.Status = jobs.StatusFinished
+		j.Progress = 1
+		j.Stage = ""
+		j.Result = p
+	})
+}
+
+// DistanceProfile runs a bounded BFS from one artist over
+// artist_collab_summary and counts how many artists sit in each ring.
+func (s *Store) DistanceProfile(
+	ctx context.Context,
+	mbid string,
+	maxDepth int,
+	progress func(progress float64, stage string),
+) (*DistanceProfile, error) {
+
+	maxDepth = ClampProfileDepth(maxDepth)
+
+	a, err := s.LookupArtistByMBID(mbid)
+	if err != nil {
+		return nil, fmt.Errorf("artist not found: %w", err)
+	}
+
+	p := &DistanceProfile{
+		MBID:     a.MBID,
+		Name:     a.Name,
+		MaxDepth: maxDepth,
+	}
+
+	seen := map[int]bool{a.ID: true}
+	frontier := []int{a.ID}
+	exampleIDs := make([][]int, 0, maxDepth)
+
+	for depth := 1; depth <= maxDepth && len(frontier) > 0 && !p.Truncated; depth++ {
+		var next []int
+
+		for i := 0; i < len(frontier) && !p.Truncated; i += profileBatchSize {
+			end := min(i+profileBatchSize, len(frontier))
+
+			nbs, err := s.NeighborIDsBatch(ctx, frontier[i:end])
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range frontier[i:end] {
+				for _, nb := range nbs[id] {
+					if seen[nb] {
+						continue
+					}
+					seen[nb] = true
+					next = append(next, nb)
+					if len(seen) > profileMaxArtists {
+						p.Truncated = true
+						break
+					}
+				}
+				if p.Truncated {
+					break
+				}
+			}
+
+			if progress != nil {
+				done := float64(depth-1) + float64(end)/float64(len(frontier))
+				progress(done/float64(maxDepth), fmt.Sprintf("depth %d", depth))
+			}
+		}
+
+		p.Rings = append(p.Rings, DistanceRing{Depth: depth, Count: len(next)})
+		exampleIDs = append(exampleIDs, next[:min(profileExamplesPerRing, len(next))])
+		frontier = next
+	}
+
+	// resolve examples in one round trip
+	var all []int
+	for _, ids := range exampleIDs {
+		all = append(all, ids...)
+	}
+	refs, err := s.ArtistRefsByID(ctx, all)
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0
+	for i := range p.Rings {
+		r := &p.Rings[i]
+		r.Examples = make([]ArtistRef, 0, len(exampleIDs[i]))
+		for _, id := range exampleIDs[i] {
+			if ref, ok := refs[id]; ok {
+				r.Examples = append(r.Examples, ref)
+			}
+		}
+		p.Reached += r.Count
+		total += r.Depth * r.Count
+	}
+	if p.Reached > 0 {
+		p.AverageSeparation = float64(total) / float64(p.Reached)
+	}
+	p.ComputedAt = time.Now().UTC()
+
+	return p, nil
+}
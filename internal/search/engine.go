@@ -0,0 +1,62 @@
+package search
+
+import (
+	"context"
+
+	sixdegrees "github.com/Jonnymurillo288/MelodyMap/sixDegrees"
+)
+
+//
+// ========================================================================
+// Search engine
+// ========================================================================
+//
+
+// Engine runs the path searches over a GraphSource. When the source is
+// the Postgres Store it also reads and fills the path cache and the pair
+// matrix, which are keyed to that database's data version.
+type Engine struct {
+	Graph GraphSource
+	store *Store // nil unless Graph is a *Store
+}
+
+// NewEngine returns an engine searching g.
+func NewEngine(g GraphSource) *Engine {
+	s, _ := g.(*Store)
+	return &Engine{Graph: g, store: s}
+}
+
+// stepsForPath loads hop evidence for a chain of artists from g and turns
+// it into the Step list a search response carries.
+func stepsForPath(ctx context.Context, g GraphSource, path []ArtistRef, tracksPerHop int) ([]Step, error) {
+	var steps []Step
+	for i := 1; i < len(path); i++ {
+		from, to := path[i-1], path[i]
+
+		tracks, err := g.EdgeEvidence(ctx, from, to, tracksPerHop)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, Step{
+			From:   from.Name,
+			To:     to.Name,
+			FromID: from.MBID,
+			ToID:   to.MBID,
+			Tracks: tracks,
+		})
+	}
+	return steps, nil
+}
+
+// pathRefs turns BFS path IDs back into artist refs.
+func pathRefs(h *sixdegrees.Helper, ids []string) []ArtistRef {
+	out := make([]ArtistRef, 0, len(ids))
+	for _, id := range ids {
+		ref := ArtistRef{MBID: id, Name: id}
+		if a, ok := h.ArtistByID[id]; ok {
+			ref.Name = a.Name
+		}
+		out = append(out, ref)
+	}
+	return out
+}
@@ -0,0 +1,365 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/game"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+//
+// ========================================================================
+// Daily challenge
+// ========================================================================
+//
+
+// ErrNoPuzzle means the pair matrix holds no pair in the difficulty band,
+// usually because cmd/pairmatrix has not been run yet.
+var ErrNoPuzzle = errors.New("no pair available for the daily puzzle")
+
+// Puzzle is one day's challenge. OptimalPath is the witness path from the
+// pair matrix; handlers withhold it until the day is over.
+type Puzzle struct {
+	Date        string      `json:"date"`
+	Start       ArtistRef   `json:"start"`
+	Target      ArtistRef   `json:"target"`
+	OptimalHops int         `json:"optimalHops"`
+	OptimalPath []ArtistRef `json:"optimalPath,omitempty"`
+
+	startID  int
+	targetID int
+}
+
+// DailyResult is the outcome of a player's attempt. Each player gets one
+// attempt per day; Resubmitted marks a later submission answered with the
+// first attempt's result.
+type DailyResult struct {
+	Date        string      `json:"date"`
+	Player      string      `json:"player"`
+	Chain       []ArtistRef `json:"chain"`
+	Valid       bool        `json:"valid"`
+	Reason      string      `json:"reason,omitempty"`
+	BrokenHop   *int        `json:"brokenHop,omitempty"` // first hop with no shared recording
+	Hops        int         `json:"hops"`
+	OptimalHops int         `json:"optimalHops"`
+	Score       int         `json:"score"`
+	OptimalPath []ArtistRef `json:"optimalPath,omitempty"`
+	Resubmitted bool        `json:"resubmitted,omitempty"`
+}
+
+// DailyScore is one line of a day's leaderboard.
+type DailyScore struct {
+	Player      string    `json:"player"`
+	Score       int       `json:"score"`
+	Hops        int       `json:"hops"`
+	SubmittedAt time.Time `json:"submittedAt"`
+}
+
+// UnknownArtistError reports a chain entry that resolved to no artist.
+type UnknownArtistError struct {
+	Index int
+	Entry string
+}
+
+func (e *UnknownArtistError) Error() string {
+	return "unknown artist: " + e.Entry
+}
+
+// MigrateGame creates the puzzle and submission tables. It is cheap and
+// idempotent; Migrate runs it and the API server runs it on start.
+func (s *Store) MigrateGame(ctx context.Context) error {
+	q := `
+		CREATE TABLE IF NOT EXISTS game_puzzle (
+			day DATE PRIMARY KEY,
+			start_id INT NOT NULL,
+			target_id INT NOT NULL,
+			optimal_hops SMALLINT NOT NULL,
+			optimal_path INT[] NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		);
+
+		CREATE TABLE IF NOT EXISTS game_submission (
+			id BIGSERIAL PRIMARY KEY,
+			day DATE NOT NULL REFERENCES game_puzzle (day),
+			player TEXT NOT NULL,
+			chain INT[] NOT NULL,
+			valid BOOLEAN NOT NULL,
+			hops SMALLINT NOT NULL,
+			score INT NOT NULL,
+			reason TEXT NOT NULL DEFAULT '',
+			broken_hop SMALLINT,
+			submitted_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		);
+
+		ALTER TABLE game_submission ADD COLUMN IF NOT EXISTS reason TEXT NOT NULL DEFAULT '';
+		ALTER TABLE game_submission ADD COLUMN IF NOT EXISTS broken_hop SMALLINT;
+
+		CREATE INDEX IF NOT EXISTS game_submission_day_score_idx
+			ON game_submission (day, score DESC);
+	`
+	if _, err := s.DB.ExecContext(ctx, q); err != nil {
+		return err
+	}
+
+	// one attempt per player and day; older tables may hold repeats, of
+	// which the first attempt is kept
+	var unique bool
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT to_regclass('game_submission_day_player_key') IS NOT NULL;
+	`).Scan(&unique)
+	if err != nil || unique {
+		return err
+	}
+	q = `
+		DELETE FROM game_submission a
+		USING game_submission b
+		WHERE a.day = b.day AND a.player = b.player AND a.id > b.id;
+
+		CREATE UNIQUE INDEX IF NOT EXISTS game_submission_day_player_key
+			ON game_submission (day, player);
+	`
+	_, err = s.DB.ExecContext(ctx, q)
+	return err
+}
+
+// DailyPuzzle returns the puzzle for day, creating it on first request by
+// picking deterministically among the pair-matrix pairs in band. Once
+// stored, a day's puzzle never changes, even if the matrix is rebuilt.
+func (s *Store) DailyPuzzle(ctx context.Context, day time.Time, band game.Band) (*Puzzle, error) {
+	day = game.Day(day)
+
+	p, err := s.loadPuzzle(ctx, day)
+	if err == nil {
+		return p, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	n, err := s.CountPairsInBand(ctx, band.Min, band.Max)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrNoPuzzle
+	}
+
+	pair, ok, err := s.PairInBand(ctx, band.Min, band.Max, game.PickIndex(day, n))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNoPuzzle
+	}
+
+	// two requests may race to create the day; the first insert wins
+	_, err = s.DB.ExecContext(ctx, `
+		INSERT INTO game_puzzle (day, start_id, target_id, optimal_hops, optimal_path)
+		SELECT $1,
+			(SELECT id FROM artist WHERE gid = $2),
+			(SELECT id FROM artist WHERE gid = $3),
+			$4,
+			ARRAY(
+				SELECT a.id
+				FROM unnest($5::uuid[]) WITH ORDINALITY AS u(gid, i)
+				JOIN artist a ON a.gid = u.gid
+				ORDER BY u.i
+			)
+		ON CONFLICT (day) DO NOTHING;
+	`, day, pair.Start.MBID, pair.Target.MBID, pair.Distance, refMBIDs(pair.Path))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadPuzzle(ctx, day)
+}
+
+func (s *Store) loadPuzzle(ctx context.Context, day time.Time) (*Puzzle, error) {
+	m := pgtype.NewMap()
+	p := &Puzzle{Date: day.Format(game.DateLayout)}
+	var path []int
+
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT start_id, target_id, optimal_hops, optimal_path
+		FROM game_puzzle
+		WHERE day = $1;
+	`, day).Scan(&p.startID, &p.targetID, &p.OptimalHops, m.SQLScanner(&path))
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := s.ArtistRefsByID(ctx, append([]int{p.startID, p.targetID}, path...))
+	if err != nil {
+		return nil, err
+	}
+	p.Start, p.Target = refs[p.startID], refs[p.targetID]
+	for _, id := range path {
+		p.OptimalPath = append(p.OptimalPath, refs[id])
+	}
+	return p, nil
+}
+
+// SubmitDaily resolves a chain of artist names or MBIDs, checks it
+// against the puzzle and artist_collab, scores it and records it as the
+// player's attempt for the day. If the player already has an attempt, it
+// is returned instead and the new chain is discarded. The result carries
+// the optimal path for handlers to withhold until the day is over.
+// Entries that match no artist yield an *UnknownArtistError and nothing
+// is recorded.
+func (s *Store) SubmitDaily(ctx context.Context, p *Puzzle, player string, entries []string) (*DailyResult, error) {
+	chain, err := s.resolveChain(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &DailyResult{
+		Date:        p.Date,
+		Player:      player,
+		Hops:        len(chain) - 1,
+		OptimalHops: p.OptimalHops,
+		OptimalPath: p.OptimalPath,
+	}
+	ids := make([]int, len(chain))
+	for i, a := range chain {
+		ids[i] = a.ID
+		res.Chain = append(res.Chain, ArtistRef{MBID: a.MBID, Name: a.Name})
+	}
+
+	if reason, ok := game.CheckChain(ids, p.startID, p.targetID); !ok {
+		res.Reason = reason
+	} else {
+		broken, err := s.MissingHops(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		if len(broken) > 0 {
+			res.Reason = "no_collaboration"
+			res.BrokenHop = &broken[0]
+		} else {
+			res.Valid = true
+			res.Score = game.Score(p.OptimalHops, res.Hops)
+		}
+	}
+
+	r, err := s.DB.ExecContext(ctx, `
+		INSERT INTO game_submission (day, player, chain, valid, hops, score, reason, broken_hop)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (day, player) DO NOTHING;
+	`, p.Date, player, ids, res.Valid, res.Hops, res.Score, res.Reason, res.BrokenHop)
+	if err != nil {
+		return nil, err
+	}
+	n, err := r.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return s.firstSubmission(ctx, p, player)
+	}
+	return res, nil
+}
+
+// firstSubmission rebuilds the result of a player's recorded attempt.
+func (s *Store) firstSubmission(ctx context.Context, p *Puzzle, player string) (*DailyResult, error) {
+	m := pgtype.NewMap()
+	res := &DailyResult{
+		Date:        p.Date,
+		Player:      player,
+		OptimalHops: p.OptimalHops,
+		OptimalPath: p.OptimalPath,
+		Resubmitted: true,
+	}
+	var ids []int
+	var brokenHop sql.NullInt32
+
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT chain, valid, hops, score, reason, broken_hop
+		FROM game_submission
+		WHERE day = $1 AND player = $2;
+	`, p.Date, player).Scan(m.SQLScanner(&ids), &res.Valid, &res.Hops, &res.Score, &res.Reason, &brokenHop)
+	if err != nil {
+		return nil, err
+	}
+	if brokenHop.Valid {
+		h := int(brokenHop.Int32)
+		res.BrokenHop = &h
+	}
+
+	refs, err := s.ArtistRefsByID(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		res.Chain = append(res.Chain, refs[id])
+	}
+	return res, nil
+}
+
+// DailyScores returns the valid attempts for a day, best first.
+func (s *Store) DailyScores(ctx context.Context, day time.Time, limit int) ([]DailyScore, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT player, score, hops, submitted_at
+		FROM game_submission
+		WHERE day = $1 AND valid
+		ORDER BY score DESC, submitted_at
+		LIMIT $2;
+	`, game.Day(day), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []DailyScore{}
+	for rows.Next() {
+		var d DailyScore
+		if err := rows.Scan(&d.Player, &d.Score, &d.Hops, &d.SubmittedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// resolveChain maps each entry, an MBID or a name, to an artist. Names go
+// through ResolveArtist and must clear ResolveAcceptNameScore.
+func (s *Store) resolveChain(ctx context.Context, entries []string) ([]ArtistInternal, error) {
+	out := make([]ArtistInternal, 0, len(entries))
+	for i, e := range entries {
+		if _, perr := uuid.Parse(e); perr == nil {
+			var a ArtistInternal
+			err := s.DB.QueryRowContext(ctx, `
+				SELECT id, gid::text, name FROM artist WHERE id = `+artistIDByGID("$1")+`;
+			`, e).Scan(&a.ID, &a.MBID, &a.Name)
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, &UnknownArtistError{Index: i, Entry: e}
+			}
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, a)
+			continue
+		}
+
+		res, err := s.ResolveArtist(ctx, e, 1)
+		if err != nil {
+			return nil, err
+		}
+		if res.Chosen == nil || res.Chosen.NameScore < ResolveAcceptNameScore {
+			return nil, &UnknownArtistError{Index: i, Entry: e}
+		}
+		out = append(out, ArtistInternal{ID: res.Chosen.ID, MBID: res.Chosen.MBID, Name: res.Chosen.Name})
+	}
+	return out, nil
+}
+
+func refMBIDs(refs []ArtistRef) []string {
+	out := make([]string, len(refs))
+	for i, r := range refs {
+		out[i] = r.MBID
+	}
+	return out
+}
@@ -0,0 +1,182 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	sixdegrees "github.com/Jonnymurillo288/MelodyMap/sixDegrees"
+	"github.com/google/uuid"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+//
+// ========================================================================
+// MusicBrainz web API graph
+// ========================================================================
+//
+
+const (
+	// mbGraphPages caps the recording pages browsed per artist; at one
+	// request per second, each uncached artist costs up to this many
+	// seconds.
+	mbGraphPages = 5
+	mbGraphCache = 2048
+)
+
+// MBGraph is a GraphSource answered live from the MusicBrainz web API:
+// two artists are neighbors when they share a recording's artist credit.
+// It needs no database but is slow, so it suits small searches and
+// development. Browsed artists are cached.
+type MBGraph struct {
+	client *MBClient
+	cache  *lru.Cache // MBID -> *mbCollabs
+}
+
+// mbCollabs is everything browsed for one artist.
+type mbCollabs struct {
+	neighbors []Neighbor
+	tracks    map[string][]TrackInfo // neighbor MBID -> shared recordings
+}
+
+// NewMBGraph returns a graph source backed by client.
+func NewMBGraph(client *MBClient) *MBGraph {
+	cache, _ := lru.New(mbGraphCache)
+	return &MBGraph{client: client, cache: cache}
+}
+
+// Resolve implements GraphSource by MBID, or by taking the best hit of
+// the MusicBrainz artist search.
+func (g *MBGraph) Resolve(ctx context.Context, query string) (*sixdegrees.Artists, error) {
+	if _, err := uuid.Parse(query); err == nil {
+		a, err := g.client.LookupArtist(query)
+		if err != nil {
+			return nil, fmt.Errorf("lookup artist %s: %w", query, err)
+		}
+		if a.ID == "" {
+			return nil, fmt.Errorf("artist %s: %w", query, sql.ErrNoRows)
+		}
+		return &sixdegrees.Artists{ID: a.ID, Name: a.Name}, nil
+	}
+
+	hits, err := g.client.SearchArtist(query)
+	if err != nil {
+		return nil, fmt.Errorf("search artist %q: %w", query, err)
+	}
+	if len(hits) == 0 {
+		return nil, fmt.Errorf("artist %q: %w", query, sql.ErrNoRows)
+	}
+	return &sixdegrees.Artists{ID: hits[0].ID, Name: hits[0].Name}, nil
+}
+
+// Neighbors implements GraphSource.
+func (g *MBGraph) Neighbors(ctx context.Context, mbid string, limit int) ([]Neighbor, error) {
+	c, err := g.collabs(ctx, mbid)
+	if err != nil {
+		return nil, err
+	}
+	nbs := c.neighbors
+	if limit > 0 && len(nbs) > limit {
+		nbs = nbs[:limit]
+	}
+	return append([]Neighbor(nil), nbs...), nil
+}
+
+// NeighborsBatch implements GraphSource one artist at a time; the API
+// has no batch browse.
+func (g *MBGraph) NeighborsBatch(ctx context.Context, mbids []string, limit int) (map[string][]Neighbor, error) {
+	out := make(map[string][]Neighbor, len(mbids))
+	for _, id := range mbids {
+		nbs, err := g.Neighbors(ctx, id, limit)
+		if err != nil {
+			return nil, err
+		}
+		if len(nbs) > 0 {
+			out[id] = nbs
+		}
+	}
+	return out, nil
+}
+
+// EdgeEvidence implements GraphSource from the browsed recordings.
+func (g *MBGraph) EdgeEvidence(ctx context.Context, from, to ArtistRef, limit int) ([]TrackInfo, error) {
+	c, err := g.collabs(ctx, from.MBID)
+	if err != nil {
+		return nil, err
+	}
+	tracks := c.tracks[to.MBID]
+	if limit > 0 && len(tracks) > limit {
+		tracks = tracks[:limit]
+	}
+	return append([]TrackInfo(nil), tracks...), nil
+}
+
+// collabs browses an artist's recordings and groups them by co-credited
+// artist.
+func (g *MBGraph) collabs(ctx context.Context, mbid string) (*mbCollabs, error) {
+	if v, ok := g.cache.Get(mbid); ok {
+		return v.(*mbCollabs), nil
+	}
+
+	c := &mbCollabs{tracks: make(map[string][]TrackInfo)}
+	names := make(map[string]string)
+
+	for page := 0; page < mbGraphPages; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := g.client.BrowseRecordings(mbid, page*mbBrowseLimit)
+		if err != nil {
+			return nil, fmt.Errorf("browse recordings of %s: %w", mbid, err)
+		}
+
+		for _, r := range resp.Recordings {
+			var credits []CreditInfo
+			for _, ac := range r.ArtistCredit {
+				credits = append(credits, CreditInfo{
+					ArtistID:   ac.Artist.ID,
+					ArtistName: ac.Artist.Name,
+					CreditedAs: ac.Name,
+				})
+			}
+
+			seen := make(map[string]bool)
+			for _, ac := range r.ArtistCredit {
+				id := ac.Artist.ID
+				if id == "" || id == mbid || seen[id] {
+					continue
+				}
+				seen[id] = true
+				names[id] = ac.Artist.Name
+				c.tracks[id] = append(c.tracks[id], TrackInfo{
+					Name:          r.Title,
+					RecordingID:   r.ID,
+					RecordingName: r.Title,
+					Credits:       credits,
+				})
+			}
+		}
+
+		if len(resp.Recordings) < mbBrowseLimit || resp.Offset+len(resp.Recordings) >= resp.Count {
+			break
+		}
+	}
+
+	for id, tracks := range c.tracks {
+		c.neighbors = append(c.neighbors, Neighbor{
+			ArtistRef: ArtistRef{MBID: id, Name: names[id]},
+			Shared:    len(tracks),
+		})
+	}
+	sort.Slice(c.neighbors, func(i, j int) bool {
+		a, b := c.neighbors[i], c.neighbors[j]
+		if a.Shared != b.Shared {
+			return a.Shared > b.Shared
+		}
+		return a.MBID < b.MBID
+	})
+
+	g.cache.Add(mbid, c)
+	return c, nil
+}
@@ -0,0 +1,395 @@
+package search
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	sixdegrees "github.com/Jonnymurillo288/MelodyMap/sixDegrees"
+)
+
+//
+// ========================================================================
+// In-memory graph (fixtures and snapshot files)
+// ========================================================================
+//
+
+// MemoryGraph is a GraphSource held entirely in memory. Build it with
+// AddArtist and AddEdge, or load it from a snapshot file, before sharing
+// it; reads are then safe for concurrent use.
+type MemoryGraph struct {
+	artists []ArtistRef
+	index   map[string]int // MBID -> position in artists
+	byName  map[string]int // normalized name -> first artist with it
+	adj     []map[int]*memoryEdge
+}
+
+// memoryEdge is shared by both directions of an undirected edge.
+type memoryEdge struct {
+	shared int
+	tracks []TrackInfo
+}
+
+// NewMemoryGraph returns an empty graph.
+func NewMemoryGraph() *MemoryGraph {
+	return &MemoryGraph{
+		index:  make(map[string]int),
+		byName: make(map[string]int),
+	}
+}
+
+// AddArtist adds an artist, or renames it if its MBID is already present,
+// and returns its position.
+func (m *MemoryGraph) AddArtist(a ArtistRef) int {
+	if i, ok := m.index[a.MBID]; ok {
+		if a.Name != "" {
+			m.artists[i].Name = a.Name
+		}
+		return i
+	}
+	i := len(m.artists)
+	m.artists = append(m.artists, a)
+	m.adj = append(m.adj, make(map[int]*memoryEdge))
+	m.index[a.MBID] = i
+	if key := sixdegrees.NormalizeArtistName(a.Name); key != "" {
+		if _, ok := m.byName[key]; !ok {
+			m.byName[key] = i
+		}
+	}
+	return i
+}
+
+// AddEdge records that two artists share recordings, adding either artist
+// if needed. shared <= 0 counts the tracks instead. Adding an edge again
+// replaces it.
+func (m *MemoryGraph) AddEdge(a, b ArtistRef, shared int, tracks []TrackInfo) {
+	if a.MBID == b.MBID {
+		return
+	}
+	if shared <= 0 {
+		shared = len(tracks)
+	}
+	i, j := m.AddArtist(a), m.AddArtist(b)
+	e := &memoryEdge{shared: shared, tracks: tracks}
+	m.adj[i][j] = e
+	m.adj[j][i] = e
+}
+
+// Len returns the number of artists.
+func (m *MemoryGraph) Len() int {
+	return len(m.artists)
+}
+
+// Resolve implements GraphSource by MBID or by normalized name.
+func (m *MemoryGraph) Resolve(ctx context.Context, query string) (*sixdegrees.Artists, error) {
+	i, ok := m.index[query]
+	if !ok {
+		i, ok = m.byName[sixdegrees.NormalizeArtistName(query)]
+	}
+	if !ok {
+		return nil, fmt.Errorf("artist %q: %w", query, sql.ErrNoRows)
+	}
+	a := m.artists[i]
+	return &sixdegrees.Artists{ID: a.MBID, Name: a.Name}, nil
+}
+
+// Neighbors implements GraphSource. limit <= 0 returns every neighbor.
+func (m *MemoryGraph) Neighbors(ctx context.Context, mbid string, limit int) ([]Neighbor, error) {
+	i, ok := m.index[mbid]
+	if !ok {
+		return nil, nil
+	}
+
+	out := make([]Neighbor, 0, len(m.adj[i]))
+	for j, e := range m.adj[i] {
+		out = append(out, Neighbor{ArtistRef: m.artists[j], Shared: e.shared})
+	}
+	sort.Slice(out, func(a, b int) bool {
+		if out[a].Shared != out[b].Shared {
+			return out[a].Shared > out[b].Shared
+		}
+		return out[a].MBID < out[b].MBID
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// NeighborsBatch implements GraphSource.
+func (m *MemoryGraph) NeighborsBatch(ctx context.Context, mbids []string, limit int) (map[string][]Neighbor, error) {
+	out := make(map[string][]Neighbor, len(mbids))
+	for _, id := range mbids {
+		nbs, err := m.Neighbors(ctx, id, limit)
+		if err != nil {
+			return nil, err
+		}
+		if len(nbs) > 0 {
+			out[id] = nbs
+		}
+	}
+	return out, nil
+}
+
+// EdgeEvidence implements GraphSource.
+func (m *MemoryGraph) EdgeEvidence(ctx context.Context, from, to ArtistRef, limit int) ([]TrackInfo, error) {
+	e := m.edge(from.MBID, to.MBID)
+	if e == nil {
+		return nil, nil
+	}
+	tracks := e.tracks
+	if limit > 0 && len(tracks) > limit {
+		tracks = tracks[:limit]
+	}
+	return append([]TrackInfo(nil), tracks...), nil
+}
+
+func (m *MemoryGraph) edge(from, to string) *memoryEdge {
+	i, ok := m.index[from]
+	if !ok {
+		return nil
+	}
+	j, ok := m.index[to]
+	if !ok {
+		return nil
+	}
+	return m.adj[i][j]
+}
+
+// StreamCollabEdges implements EdgeStreamer. Artist ids are positions
+// plus one, in the order artists were added; ArtistAt maps them back.
+func (m *MemoryGraph) StreamCollabEdges(ctx context.Context, fn func(artistID, neighborID, shared int) error) error {
+	for i, nbs := range m.adj {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ids := make([]int, 0, len(nbs))
+		for j := range nbs {
+			ids = append(ids, j)
+		}
+		sort.Ints(ids)
+		for _, j := range ids {
+			if err := fn(i+1, j+1, nbs[j].shared); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ArtistAt returns the artist with a StreamCollabEdges id.
+func (m *MemoryGraph) ArtistAt(id int) (ArtistRef, bool) {
+	if id < 1 || id > len(m.artists) {
+		return ArtistRef{}, false
+	}
+	return m.artists[id-1], true
+}
+
+//
+// ------------------------------------------------------------------------
+// Snapshot files
+// ------------------------------------------------------------------------
+//
+
+// SnapshotVersion is the snapshot file format written by WriteSnapshot.
+const SnapshotVersion = 1
+
+// graphSnapshot is the JSON layout of a snapshot file. Each undirected
+// edge appears once.
+type graphSnapshot struct {
+	Version int            `json:"version"`
+	Artists []ArtistRef    `json:"artists"`
+	Edges   []snapshotEdge `json:"edges"`
+}
+
+type snapshotEdge struct {
+	From   string      `json:"from"`
+	To     string      `json:"to"`
+	Shared int         `json:"shared"`
+	Tracks []TrackInfo `json:"tracks,omitempty"`
+}
+
+// LoadSnapshot reads a snapshot file; names ending in .gz are gunzipped.
+func LoadSnapshot(path string) (*MemoryGraph, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot %s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	m, err := ReadSnapshot(r)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// ReadSnapshot decodes a snapshot.
+func ReadSnapshot(r io.Reader) (*MemoryGraph, error) {
+	var snap graphSnapshot
+	if err := json.NewDecoder(r).Decode(&snap); err != nil {
+		return nil, err
+	}
+	if snap.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", snap.Version)
+	}
+
+	m := NewMemoryGraph()
+	for _, a := range snap.Artists {
+		m.AddArtist(a)
+	}
+	for _, e := range snap.Edges {
+		from, ok := m.index[e.From]
+		if !ok {
+			return nil, fmt.Errorf("edge from unknown artist %s", e.From)
+		}
+		to, ok := m.index[e.To]
+		if !ok {
+			return nil, fmt.Errorf("edge to unknown artist %s", e.To)
+		}
+		m.AddEdge(m.artists[from], m.artists[to], e.Shared, e.Tracks)
+	}
+	return m, nil
+}
+
+// WriteSnapshot encodes the graph in the format ReadSnapshot reads.
+func (m *MemoryGraph) WriteSnapshot(w io.Writer) error {
+	snap := graphSnapshot{
+		Version: SnapshotVersion,
+		Artists: m.artists,
+	}
+	for i, nbs := range m.adj {
+		ids := make([]int, 0, len(nbs))
+		for j := range nbs {
+			if j > i {
+				ids = append(ids, j)
+			}
+		}
+		sort.Ints(ids)
+		for _, j := range ids {
+			e := nbs[j]
+			snap.Edges = append(snap.Edges, snapshotEdge{
+				From:   m.artists[i].MBID,
+				To:     m.artists[j].MBID,
+				Shared: e.shared,
+				Tracks: e.tracks,
+			})
+		}
+	}
+	return json.NewEncoder(w).Encode(snap)
+}
+
+// SnapshotNeighborhood copies the part of src within depth hops of the
+// seed artists into a MemoryGraph, keeping the limit strongest neighbors
+// of each artist and up to tracksPerEdge tracks of evidence per edge.
+// Edges between two artists at the outer ring are not loaded.
+func SnapshotNeighborhood(
+	ctx context.Context,
+	src GraphSource,
+	seeds []ArtistRef,
+	depth, limit, tracksPerEdge int,
+) (*MemoryGraph, error) {
+
+	m := NewMemoryGraph()
+	frontier := make([]string, 0, len(seeds))
+	for _, a := range seeds {
+		if _, ok := m.index[a.MBID]; !ok {
+			m.AddArtist(a)
+			frontier = append(frontier, a.MBID)
+		}
+	}
+
+	for d := 0; d < depth && len(frontier) > 0; d++ {
+		var next []string
+		for i := 0; i < len(frontier); i += bfsBatchSize {
+			batch := frontier[i:min(i+bfsBatchSize, len(frontier))]
+			nbs, err := src.NeighborsBatch(ctx, batch, limit)
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range batch {
+				from := m.artists[m.index[id]]
+				var fresh []Neighbor
+				for _, nb := range nbs[id] {
+					if _, ok := m.index[nb.MBID]; !ok {
+						next = append(next, nb.MBID)
+					}
+					if m.edge(id, nb.MBID) == nil {
+						fresh = append(fresh, nb)
+					}
+				}
+
+				// one evidence lookup per expanded artist
+				var evidence map[string][]TrackInfo
+				if tracksPerEdge > 0 && len(fresh) > 0 {
+					to := make([]ArtistRef, len(fresh))
+					for j, nb := range fresh {
+						to[j] = nb.ArtistRef
+					}
+					evidence, err = EdgeEvidenceFor(ctx, src, from, to, tracksPerEdge)
+					if err != nil {
+						return nil, err
+					}
+				}
+				for _, nb := range fresh {
+					m.AddEdge(from, nb.ArtistRef, nb.Shared, evidence[nb.MBID])
+				}
+			}
+		}
+		frontier = next
+	}
+	return m, nil
+}
+
+//
+// ------------------------------------------------------------------------
+// Fixture
+// ------------------------------------------------------------------------
+//
+
+// FixtureGraph returns a small hand-made graph for local development and
+// tests: a chain of five artists with one branch.
+//
+//	Alpha - Bravo - Charlie - Delta - Echo
+//	                   |
+//	                Foxtrot
+func FixtureGraph() *MemoryGraph {
+	ref := func(n int, name string) ArtistRef {
+		return ArtistRef{MBID: fmt.Sprintf("00000000-0000-4000-8000-%012d", n), Name: name}
+	}
+	track := func(n int, title string) []TrackInfo {
+		return []TrackInfo{{
+			ID:            fmt.Sprintf("00000000-0000-4000-9000-%012d", n),
+			Name:          title,
+			RecordingID:   fmt.Sprintf("00000000-0000-4000-a000-%012d", n),
+			RecordingName: title,
+		}}
+	}
+
+	alpha, bravo, charlie := ref(1, "Alpha"), ref(2, "Bravo"), ref(3, "Charlie")
+	delta, echo, foxtrot := ref(4, "Delta"), ref(5, "Echo"), ref(6, "Foxtrot")
+
+	m := NewMemoryGraph()
+	m.AddEdge(alpha, bravo, 5, track(1, "Alpha & Bravo"))
+	m.AddEdge(bravo, charlie, 2, track(2, "Bravo feat. Charlie"))
+	m.AddEdge(charlie, delta, 3, track(3, "Charlie x Delta"))
+	m.AddEdge(delta, echo, 1, track(4, "Delta with Echo"))
+	m.AddEdge(charlie, foxtrot, 4, track(5, "Foxtrot Sessions"))
+	return m
+}
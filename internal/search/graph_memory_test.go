@@ -0,0 +1,128 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestMemoryGraph_Resolve(t *testing.T) {
+	g := FixtureGraph()
+	ctx := context.Background()
+
+	a, err := g.Resolve(ctx, "the charlie")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Name != "Charlie" {
+		t.Fatalf("expected Charlie, got %q", a.Name)
+	}
+	if b, err := g.Resolve(ctx, a.ID); err != nil || b.Name != "Charlie" {
+		t.Fatalf("resolve by MBID: %v, %v", b, err)
+	}
+	if _, err := g.Resolve(ctx, "Zulu"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestMemoryGraph_NeighborsRankedAndLimited(t *testing.T) {
+	g := FixtureGraph()
+	ctx := context.Background()
+	charlie, _ := g.Resolve(ctx, "Charlie")
+
+	nbs, err := g.Neighbors(ctx, charlie.ID, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, nb := range nbs {
+		got = append(got, fmt.Sprintf("%s:%d", nb.Name, nb.Shared))
+	}
+	if fmt.Sprint(got) != "[Foxtrot:4 Delta:3]" {
+		t.Fatalf("unexpected neighbors %v", got)
+	}
+}
+
+func TestSnapshot_RoundTrip(t *testing.T) {
+	g := FixtureGraph()
+
+	var buf bytes.Buffer
+	if err := g.WriteSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	back, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Len() != g.Len() {
+		t.Fatalf("expected %d artists, got %d", g.Len(), back.Len())
+	}
+
+	ctx := context.Background()
+	delta, _ := back.Resolve(ctx, "Delta")
+	echo, _ := back.Resolve(ctx, "Echo")
+	tracks, err := back.EdgeEvidence(ctx,
+		ArtistRef{MBID: echo.ID, Name: echo.Name},
+		ArtistRef{MBID: delta.ID, Name: delta.Name}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tracks) != 1 || tracks[0].Name != "Delta with Echo" {
+		t.Fatalf("unexpected evidence %+v", tracks)
+	}
+}
+
+func TestEngine_SearchesMemoryGraph(t *testing.T) {
+	e := NewEngine(FixtureGraph())
+
+	hops, steps, msg, status, err := e.SearchArtists("Alpha", "Foxtrot", 5, 100)
+	if err != nil || status != 200 {
+		t.Fatalf("search failed: %d %q %v", status, msg, err)
+	}
+	if hops != 3 {
+		t.Fatalf("expected 3 hops, got %d", hops)
+	}
+	var names []string
+	for _, s := range steps {
+		names = append(names, s.To)
+		if len(s.Tracks) == 0 {
+			t.Fatalf("hop %s -> %s has no evidence", s.From, s.To)
+		}
+	}
+	if fmt.Sprint(names) != "[Bravo Charlie Foxtrot]" {
+		t.Fatalf("unexpected path %v", names)
+	}
+
+	if _, ok := e.CachedSearch(context.Background(), SearchRequest{Start: "Alpha", Target: "Echo"}); ok {
+		t.Fatalf("memory graph engines have no path cache")
+	}
+}
+
+// failingGraph is FixtureGraph with every neighbor lookup failing.
+type failingGraph struct{ *MemoryGraph }
+
+func (failingGraph) NeighborsBatch(context.Context, []string, int) (map[string][]Neighbor, error) {
+	return nil, errors.New("source unavailable")
+}
+
+func TestEngine_ReportsSourceErrors(t *testing.T) {
+	e := NewEngine(failingGraph{FixtureGraph()})
+
+	_, _, _, status, err := e.SearchArtists("Alpha", "Echo", 5, 100)
+	if status != 502 || err == nil {
+		t.Fatalf("expected 502 with an error, got %d %v", status, err)
+	}
+}
+
+func TestLoadCollabGraph_FromMemoryGraph(t *testing.T) {
+	g, err := LoadCollabGraph(context.Background(), FixtureGraph(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.NumNodes() != 6 {
+		t.Fatalf("expected 6 nodes, got %d", g.NumNodes())
+	}
+}
@@ -0,0 +1,248 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/analytics"
+	sixdegrees "github.com/Jonnymurillo288/MelodyMap/sixDegrees"
+)
+
+//
+// ========================================================================
+// Graph sources
+// ========================================================================
+//
+// The path searches only need four things from the collaboration graph,
+// captured by GraphSource. *Store answers them from Postgres; SQLiteStore
+// from a trimmed single-file copy; MemoryGraph from a fixture or a
+// snapshot file; MBGraph from the MusicBrainz web API. GraphSourceFromEnv
+// picks one.
+
+// GraphSource is the collaboration graph as seen by the search engine.
+// Artists are identified by MBID.
+type GraphSource interface {
+	// Resolve maps a name, MBID or Spotify artist URI to an artist.
+	Resolve(ctx context.Context, query string) (*sixdegrees.Artists, error)
+	// Neighbors returns up to limit collaborators, strongest first.
+	Neighbors(ctx context.Context, mbid string, limit int) ([]Neighbor, error)
+	// NeighborsBatch returns up to limit collaborators of each artist.
+	NeighborsBatch(ctx context.Context, mbids []string, limit int) (map[string][]Neighbor, error)
+	// EdgeEvidence returns up to limit tracks two artists share.
+	EdgeEvidence(ctx context.Context, from, to ArtistRef, limit int) ([]TrackInfo, error)
+}
+
+// EdgeStreamer is implemented by graph sources that can enumerate every
+// edge, which the offline analytics need. Artist ids are dense integers;
+// fn is called grouped by artistID in ascending order, both directions.
+type EdgeStreamer interface {
+	StreamCollabEdges(ctx context.Context, fn func(artistID, neighborID, shared int) error) error
+}
+
+// EvidenceBatcher is implemented by graph sources that can load the
+// evidence from one artist to many neighbors in a single round trip.
+type EvidenceBatcher interface {
+	EdgeEvidenceBatch(ctx context.Context, from ArtistRef, to []ArtistRef, limit int) (map[string][]TrackInfo, error)
+}
+
+// EdgeEvidenceFor loads up to limit tracks from one artist to each of to,
+// keyed by neighbor MBID, batched when g supports it.
+func EdgeEvidenceFor(ctx context.Context, g GraphSource, from ArtistRef, to []ArtistRef, limit int) (map[string][]TrackInfo, error) {
+	if b, ok := g.(EvidenceBatcher); ok {
+		return b.EdgeEvidenceBatch(ctx, from, to, limit)
+	}
+	out := make(map[string][]TrackInfo, len(to))
+	for _, nb := range to {
+		tracks, err := g.EdgeEvidence(ctx, from, nb, limit)
+		if err != nil {
+			return nil, err
+		}
+		out[nb.MBID] = tracks
+	}
+	return out, nil
+}
+
+// Neighbor is one collaborator of an artist.
+type Neighbor struct {
+	ArtistRef
+	Shared int `json:"shared"` // shared recordings
+}
+
+// Graph source names accepted by GRAPH_SOURCE.
+const (
+	GraphSourcePostgres = "postgres"
+	GraphSourceMemory   = "memory"
+	GraphSourceSnapshot = "snapshot"
+	GraphSourceMBAPI    = "mbapi"
+	GraphSourceSQLite   = "sqlite"
+)
+
+// GraphSourceUsesDB reports whether GRAPH_SOURCE selects the Postgres
+// store; the other sources can serve searches without it.
+func GraphSourceUsesDB() bool {
+	name := os.Getenv("GRAPH_SOURCE")
+	return name == "" || name == GraphSourcePostgres
+}
+
+// GraphSourceFromEnv returns the graph source named by GRAPH_SOURCE:
+// postgres (the default, backed by store), memory (the built-in fixture),
+// snapshot (the file at GRAPH_SNAPSHOT), sqlite (the file at
+// GRAPH_SQLITE, see cmd/sqlite) or mbapi (the MusicBrainz web API, rate
+// limited to one request per second).
+func GraphSourceFromEnv(store *Store) (GraphSource, error) {
+	switch name := os.Getenv("GRAPH_SOURCE"); name {
+	case "", GraphSourcePostgres:
+		if store == nil {
+			return nil, fmt.Errorf("graph source %s needs a database", GraphSourcePostgres)
+		}
+		return store, nil
+	case GraphSourceMemory:
+		return FixtureGraph(), nil
+	case GraphSourceSnapshot:
+		path := os.Getenv("GRAPH_SNAPSHOT")
+		if path == "" {
+			return nil, fmt.Errorf("graph source %s needs GRAPH_SNAPSHOT", GraphSourceSnapshot)
+		}
+		return LoadSnapshot(path)
+	case GraphSourceSQLite:
+		path := os.Getenv("GRAPH_SQLITE")
+		if path == "" {
+			return nil, fmt.Errorf("graph source %s needs GRAPH_SQLITE", GraphSourceSQLite)
+		}
+		return OpenSQLite(path)
+	case GraphSourceMBAPI:
+		return NewMBGraph(NewMBClient()), nil
+	default:
+		return nil, fmt.Errorf("unknown graph source %q", name)
+	}
+}
+
+// LoadCollabGraph reads a whole graph into the CSR form used by the
+// analytics. progress, if set, is called every million edges.
+func LoadCollabGraph(ctx context.Context, src EdgeStreamer, progress func(edges int)) (*analytics.Graph, error) {
+	b := analytics.NewBuilder()
+	n := 0
+	err := src.StreamCollabEdges(ctx, func(a, nb, shared int) error {
+		n++
+		if progress != nil && n%1_000_000 == 0 {
+			progress(n)
+		}
+		return b.Add(a, nb, shared)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b.Build(), nil
+}
+
+//
+// ------------------------------------------------------------------------
+// Postgres
+// ------------------------------------------------------------------------
+//
+
+// Resolve implements GraphSource.
+func (s *Store) Resolve(ctx context.Context, query string) (*sixdegrees.Artists, error) {
+	return s.resolveEndpoint(ctx, query)
+}
+
+// Neighbors implements GraphSource from artist_collab_summary.
+func (s *Store) Neighbors(ctx context.Context, mbid string, limit int) ([]Neighbor, error) {
+	sums, err := s.ListCollabSummaries(ctx, mbid, limit)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Neighbor, 0, len(sums))
+	for _, c := range sums {
+		out = append(out, Neighbor{
+			ArtistRef: ArtistRef{MBID: c.NeighborMBID, Name: c.NeighborName},
+			Shared:    c.SharedRecordings,
+		})
+	}
+	return out, nil
+}
+
+// NeighborsBatch implements GraphSource with one query for all artists.
+// MBIDs are expected to be canonical, as returned by Resolve or Neighbors.
+func (s *Store) NeighborsBatch(ctx context.Context, mbids []string, limit int) (map[string][]Neighbor, error) {
+	out := make(map[string][]Neighbor, len(mbids))
+	if len(mbids) == 0 {
+		return out, nil
+	}
+	if limit <= 0 {
+		limit = 200
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT a1.gid::text, a2.gid::text, a2.name, ranked.shared_recordings
+		FROM (
+			SELECT
+				cs.artist_id,
+				cs.neighbor_artist_id,
+				cs.shared_recordings,
+				row_number() OVER (
+					PARTITION BY cs.artist_id
+					ORDER BY cs.shared_recordings DESC, cs.neighbor_artist_id
+				) AS rn
+			FROM artist_collab_summary cs
+			JOIN artist a ON a.id = cs.artist_id
+			WHERE a.gid = ANY($1::uuid[])
+		) ranked
+		JOIN artist a1 ON a1.id = ranked.artist_id
+		JOIN artist a2 ON a2.id = ranked.neighbor_artist_id
+		WHERE ranked.rn <= $2
+		ORDER BY ranked.artist_id, ranked.rn;
+	`, mbids, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var from string
+		var nb Neighbor
+		if err := rows.Scan(&from, &nb.MBID, &nb.Name, &nb.Shared); err != nil {
+			return nil, err
+		}
+		out[from] = append(out[from], nb)
+	}
+	return out, rows.Err()
+}
+
+// EdgeEvidence implements GraphSource from the collaboration query,
+// dropping near-duplicate tracks.
+func (s *Store) EdgeEvidence(ctx context.Context, from, to ArtistRef, limit int) ([]TrackInfo, error) {
+	tw, err := s.GetEdgeTracks(ctx, &sixdegrees.Artists{ID: from.MBID, Name: from.Name}, to.MBID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return edgeTrackInfos(tw), nil
+}
+
+// EdgeEvidenceBatch implements EvidenceBatcher.
+func (s *Store) EdgeEvidenceBatch(ctx context.Context, from ArtistRef, to []ArtistRef, limit int) (map[string][]TrackInfo, error) {
+	mbids := make([]string, len(to))
+	for i, nb := range to {
+		mbids[i] = nb.MBID
+	}
+	byNeighbor, err := s.GetEdgeTracksBatch(ctx, &sixdegrees.Artists{ID: from.MBID, Name: from.Name}, mbids, limit)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string][]TrackInfo, len(byNeighbor))
+	for mbid, tw := range byNeighbor {
+		out[mbid] = edgeTrackInfos(tw)
+	}
+	return out, nil
+}
+
+// edgeTrackInfos deduplicates the raw tracks of one edge.
+func edgeTrackInfos(tw []TrackWrapper) []TrackInfo {
+	tracks := sixdegrees.DeduplicateTracks(ConvertTrackList(tw), 0.65, false)
+	out := make([]TrackInfo, 0, len(tracks))
+	for _, t := range tracks {
+		out = append(out, TrackInfoFromTrack(t))
+	}
+	return out
+}
@@ -0,0 +1,222 @@
+package search
+
+import (
+	"context"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/analytics"
+)
+
+//
+// ========================================================================
+// Collaboration link prediction
+// ========================================================================
+//
+
+const (
+	// linkPredMaxNeighbors caps how many of the source's own collaborators
+	// seed candidate generation (strongest first). Candidates are still
+	// checked against all of them.
+	linkPredMaxNeighbors = 200
+	// linkPredPerNeighbor caps how many collaborators are expanded per
+	// neighbor, so a single hub cannot flood the 2-hop neighborhood.
+	linkPredPerNeighbor = 500
+	// linkPredSharedShown is how many justifying neighbors are returned
+	// per prediction.
+	linkPredSharedShown = 5
+)
+
+// PredictedCollaborator is one artist the source has not worked with yet,
+// scored from the collaborators they have in common.
+type PredictedCollaborator struct {
+	ArtistRef
+	AdamicAdar      float64     `json:"adamicAdar"`
+	Jaccard         float64     `json:"jaccard"`
+	CommonNeighbors int         `json:"commonNeighbors"`
+	SharedNeighbors []ArtistRef `json:"sharedNeighbors"`
+}
+
+// PredictCollaborators ranks the 2-hop neighborhood of an artist in
+// artist_collab_summary by Adamic-Adar, with Jaccard and the common
+// neighbor count alongside. Returns sql.ErrNoRows for an unknown MBID.
+func (s *Store) PredictCollaborators(ctx context.Context, mbid string, limit int) ([]PredictedCollaborator, error) {
+	a, err := s.LookupArtistByMBID(mbid)
+	if err != nil {
+		return nil, err
+	}
+
+	first, err := s.TopNeighborIDsBatch(ctx, []int{a.ID}, linkPredMaxNeighbors)
+	if err != nil {
+		return nil, err
+	}
+	neighbors := first[a.ID]
+	if len(neighbors) == 0 {
+		return []PredictedCollaborator{}, nil
+	}
+
+	neighborsOf, err := s.TopNeighborIDsBatch(ctx, neighbors, linkPredPerNeighbor)
+	if err != nil {
+		return nil, err
+	}
+
+	// true degrees for the source, its neighbors and every candidate
+	seen := map[int]bool{a.ID: true}
+	ids := []int{a.ID}
+	for _, n := range neighbors {
+		if !seen[n] {
+			seen[n] = true
+			ids = append(ids, n)
+		}
+		for _, c := range neighborsOf[n] {
+			if !seen[c] {
+				seen[c] = true
+				ids = append(ids, c)
+			}
+		}
+	}
+	degree, err := s.CollabDegrees(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// collaborators beyond the seeding cap are not predictions either
+	known, err := s.collabsAmong(ctx, a.ID, ids)
+	if err != nil {
+		return nil, err
+	}
+	for n, cs := range neighborsOf {
+		kept := cs[:0]
+		for _, c := range cs {
+			if !known[c] {
+				kept = append(kept, c)
+			}
+		}
+		neighborsOf[n] = kept
+	}
+
+	preds := analytics.PredictLinks(a.ID, neighbors, neighborsOf, degree, limit)
+
+	refIDs := make([]int, 0, len(preds)*(linkPredSharedShown+1))
+	for _, p := range preds {
+		refIDs = append(refIDs, p.ID)
+		refIDs = append(refIDs, p.CommonNeighbors[:min(len(p.CommonNeighbors), linkPredSharedShown)]...)
+	}
+	refs, err := s.ArtistRefsByID(ctx, refIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]PredictedCollaborator, 0, len(preds))
+	for _, p := range preds {
+		pc := PredictedCollaborator{
+			ArtistRef:       refs[p.ID],
+			AdamicAdar:      p.AdamicAdar,
+			Jaccard:         p.Jaccard,
+			CommonNeighbors: len(p.CommonNeighbors),
+			SharedNeighbors: make([]ArtistRef, 0, linkPredSharedShown),
+		}
+		// CommonNeighbors follows the source's strongest collaborations first
+		for _, n := range p.CommonNeighbors[:min(len(p.CommonNeighbors), linkPredSharedShown)] {
+			if ref, ok := refs[n]; ok {
+				pc.SharedNeighbors = append(pc.SharedNeighbors, ref)
+			}
+		}
+		out = append(out, pc)
+	}
+	return out, nil
+}
+
+// TopNeighborIDsBatch is NeighborIDsBatch limited to each artist's k
+// strongest collaborators by shared recordings.
+func (s *Store) TopNeighborIDsBatch(ctx context.Context, ids []int, k int) (map[int][]int, error) {
+	out := make(map[int][]int, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	q := `
+		SELECT artist_id, neighbor_artist_id
+		FROM (
+			SELECT
+				artist_id,
+				neighbor_artist_id,
+				shared_recordings,
+				row_number() OVER (
+					PARTITION BY artist_id
+					ORDER BY shared_recordings DESC, neighbor_artist_id
+				) AS rn
+			FROM artist_collab_summary
+			WHERE artist_id = ANY($1)
+		) ranked
+		WHERE rn <= $2
+		ORDER BY artist_id, rn;
+	`
+	rows, err := s.DB.QueryContext(ctx, q, ids, k)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a, nb int
+		if err := rows.Scan(&a, &nb); err != nil {
+			return nil, err
+		}
+		out[a] = append(out[a], nb)
+	}
+	return out, rows.Err()
+}
+
+// CollabDegrees returns the number of distinct collaborators of each artist.
+func (s *Store) CollabDegrees(ctx context.Context, ids []int) (map[int]int, error) {
+	out := make(map[int]int, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT artist_id, count(*)
+		FROM artist_collab_summary
+		WHERE artist_id = ANY($1)
+		GROUP BY artist_id;
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a, d int
+		if err := rows.Scan(&a, &d); err != nil {
+			return nil, err
+		}
+		out[a] = d
+	}
+	return out, rows.Err()
+}
+
+// collabsAmong reports which of ids the artist has collaborated with.
+func (s *Store) collabsAmong(ctx context.Context, id int, ids []int) (map[int]bool, error) {
+	out := make(map[int]bool)
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT neighbor_artist_id
+		FROM artist_collab_summary
+		WHERE artist_id = $1 AND neighbor_artist_id = ANY($2);
+	`, id, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var nb int
+		if err := rows.Scan(&nb); err != nil {
+			return nil, err
+		}
+		out[nb] = true
+	}
+	return out, rows.Err()
+}
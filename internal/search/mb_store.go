@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 )
 
 //
@@ -17,23 +19,40 @@ import (
 // ========================================================================
 //
 
+// Store wraps the MusicBrainz database. It holds a connection pool and is
+// safe for concurrent use; open one per process and share it.
 type Store struct {
 	DB *sql.DB
 }
 
+// Open connects to Postgres with the pool sized by PoolConfigFromEnv. The
+// musicbrainz search_path is set as a startup parameter of every pooled
+// connection, unless the DSN already chooses one. An empty dsn reads
+// PG_DSN.
 func Open(dsn string) (*Store, error) {
 	if dsn == "" {
 		dsn = os.Getenv("PG_DSN")
 	}
 
-	log.Println("[DB] Opening DB with DSN:", dsn)
+	pool, err := PoolConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
 
-	db, err := sql.Open("pgx", dsn)
+	cfg, err := pgx.ParseConfig(dsn)
 	if err != nil {
-		return nil, fmt.Errorf("open db: %w", err)
+		return nil, fmt.Errorf("parse dsn: %w", err)
+	}
+	_, hasPath := cfg.RuntimeParams["search_path"]
+	if !hasPath && !strings.Contains(cfg.RuntimeParams["options"], "search_path") {
+		cfg.RuntimeParams["search_path"] = "musicbrainz"
 	}
 
-	log.Println("[DB] DB opened, pinging...")
+	db := stdlib.OpenDB(*cfg)
+	db.SetMaxOpenConns(pool.MaxOpen)
+	db.SetMaxIdleConns(pool.MaxIdle)
+	db.SetConnMaxLifetime(pool.MaxLifetime)
+	db.SetConnMaxIdleTime(pool.MaxIdleTime)
 
 	// Ping with timeout
 	err = withTimeout(func(ctx context.Context) error {
@@ -44,25 +63,8 @@ func Open(dsn string) (*Store, error) {
 		return nil, fmt.Errorf("db ping: %w", err)
 	}
 
-	log.Println("[DB] Ping OK")
-
-	// Set search path
-	if _, err := db.Exec("SET search_path TO musicbrainz;"); err != nil {
-		log.Printf("[DB] FAILED to set search_path: %v", err)
-	} else {
-		log.Println("[DB] search_path set to musicbrainz")
-	}
-
-	// TEST: count rows in artist_collab
-	var cnt int
-	err = db.QueryRow(`SELECT count(*) FROM artist_collab`).Scan(&cnt)
-	if err != nil {
-		log.Printf("[DB] artist_collab count FAILED: %v", err)
-	} else {
-		log.Printf("[DB] artist_collab rows detected: %d", cnt)
-	}
-
-	log.Println("[DB] Open() complete")
+	log.Printf("[DB] connected to %s/%s (max open %d, max idle %d)",
+		cfg.Host, cfg.Database, pool.MaxOpen, pool.MaxIdle)
 
 	return &Store{DB: db}, nil
 }
@@ -110,11 +112,13 @@ type ArtistInternal struct {
 	Name string
 }
 
+// LookupArtistByMBID finds an artist by MBID, following merge redirects;
+// the returned MBID is the canonical one.
 func (s *Store) LookupArtistByMBID(mbid string) (*ArtistInternal, error) {
 	q := `
         SELECT id, gid::text, name
         FROM artist
-        WHERE gid = $1
+        WHERE id = ` + artistIDByGID("$1") + `
         LIMIT 1;
     `
 	var a ArtistInternal
@@ -125,6 +129,41 @@ func (s *Store) LookupArtistByMBID(mbid string) (*ArtistInternal, error) {
 	return &a, nil
 }
 
+// ArtistRef is the minimal public identity of an artist.
+type ArtistRef struct {
+	MBID string `json:"mbid"`
+	Name string `json:"name"`
+}
+
+// ArtistRefsByID maps internal artist ids to their MBID and name.
+func (s *Store) ArtistRefsByID(ctx context.Context, ids []int) (map[int]ArtistRef, error) {
+	out := make(map[int]ArtistRef, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	q := `
+		SELECT id, gid::text, name
+		FROM artist
+		WHERE id = ANY($1);
+	`
+	rows, err := s.DB.QueryContext(ctx, q, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var ref ArtistRef
+		if err := rows.Scan(&id, &ref.MBID, &ref.Name); err != nil {
+			return nil, err
+		}
+		out[id] = ref
+	}
+	return out, rows.Err()
+}
+
 //
 // ========================================================================
 // Performer-only collaboration filter
@@ -155,7 +194,7 @@ func (s *Store) GetArtistTracksDB(
 
 	q := `
 		WITH main_artist AS (
-			SELECT id FROM artist WHERE gid = $1
+			SELECT ` + artistIDByGID("$1") + ` AS id
 		)
 		SELECT
 			r.gid::text        AS recording_mbid,
@@ -280,15 +319,15 @@ func (s *Store) Migrate(ctx context.Context) error {
 		);`
 
 	ind := `
-	CREATE INDEX artist_collab_artist_idx
-    ON artist_collab (artist_id);
+		CREATE INDEX IF NOT EXISTS artist_collab_artist_idx
+			ON artist_collab (artist_id);
 
-	CREATE INDEX artist_collab_neighbor_idx
-		ON artist_collab (neighbor_artist_id);
+		CREATE INDEX IF NOT EXISTS artist_collab_neighbor_idx
+			ON artist_collab (neighbor_artist_id);
 
-	CREATE INDEX artist_collab_rec_idx
-		ON artist_collab (recording_id);
-`
+		CREATE INDEX IF NOT EXISTS artist_collab_rec_idx
+			ON artist_collab (recording_id);
+	`
 
 	ins := `
 		INSERT INTO artist_collab (artist_id, neighbor_artist_id, recording_id)
@@ -304,19 +343,45 @@ func (s *Store) Migrate(ctx context.Context) error {
 		ON CONFLICT DO NOTHING;
 		`
 
-	_, err := s.DB.Query(q)
+	_, err := s.DB.ExecContext(ctx, q)
 	if err != nil {
 		return err
 	}
-	_, err = s.DB.Query(ind)
+	_, err = s.DB.ExecContext(ctx, ind)
 	if err != nil {
 		return err
 	}
 
-	_, err = s.DB.Query(ins)
+	// the watermark is taken before the rebuild reads anything, so
+	// changes made while it runs are seen again by UpdateCollab
+	if err := s.migratePathCache(ctx); err != nil {
+		return err
+	}
+	if err := s.migrateCollabIncremental(ctx); err != nil {
+		return err
+	}
+
+	_, err = s.DB.ExecContext(ctx, ins)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	if err := s.migrateCollabSummary(ctx); err != nil {
+		return err
+	}
+	if err := s.MigrateAnalytics(ctx); err != nil {
+		return err
+	}
+	if err := s.MigrateGame(ctx); err != nil {
+		return err
+	}
+	if err := s.migrateResolver(ctx); err != nil {
+		return err
+	}
+	if _, err := s.RebuildSpotifyCrosswalk(ctx); err != nil {
+		return err
+	}
+	// the collaboration tables were just rebuilt: retire cached paths
+	_, err = s.BumpDataVersion(ctx)
+	return err
 }
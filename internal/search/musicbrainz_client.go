@@ -1,14 +1,11 @@
 package search
 
 import (
-	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"time"
-
-	sixdegrees "github.com/Jonnymurillo288/MelodyMap/sixDegrees"
 )
 
 const mbBaseURL = "https://musicbrainz.org/ws/2"
@@ -105,40 +102,41 @@ func (c *MBClient) LookupArtist(id string) (*mbArtistLookup, error) {
 	return &resp, nil
 }
 
-// NOT A CLIENT BY DB, GOING TO FIX IN THE FUTURE
+// ------------
+// Browse API
+// ------------
 
-func ResolveArtistOnce(dsn, name string) (*sixdegrees.Artists, error) {
-	db, err := sql.Open("pgx", dsn)
-	if err != nil {
-		return nil, fmt.Errorf("open db: %w", err)
-	}
-	defer db.Close()
-
-	// set schema
-	_, _ = db.Exec("SET search_path TO musicbrainz;")
-
-	// IMPORTANT: order by id ASC so the canonical artist is chosen
-	const q = `
-        SELECT id, gid, name
-        FROM artist
-        WHERE lower(name) = lower($1)
-        ORDER BY id ASC
-        LIMIT 1;
-    `
-
-	var (
-		internalID int
-		mbid       string
-		cname      string
-	)
-
-	err = db.QueryRow(q, name).Scan(&internalID, &mbid, &cname)
-	if err != nil {
-		return nil, fmt.Errorf("artist not found: %w", err)
-	}
+// mbBrowseLimit is the largest page the browse endpoints return.
+const mbBrowseLimit = 100
+
+type mbRecordingBrowse struct {
+	Count      int           `json:"recording-count"`
+	Offset     int           `json:"recording-offset"`
+	Recordings []mbRecording `json:"recordings"`
+}
+
+type mbRecording struct {
+	ID           string           `json:"id"`
+	Title        string           `json:"title"`
+	ArtistCredit []mbArtistCredit `json:"artist-credit"`
+}
 
-	return &sixdegrees.Artists{
-		ID:   mbid, // UUID (gid)
-		Name: cname,
-	}, nil
+type mbArtistCredit struct {
+	Name   string `json:"name"`
+	Artist struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"artist"`
+}
+
+// BrowseRecordings lists one page of an artist's recordings with their
+// artist credits.
+func (c *MBClient) BrowseRecordings(artistID string, offset int) (*mbRecordingBrowse, error) {
+	u := fmt.Sprintf("%s/recording?artist=%s&inc=artist-credits&limit=%d&offset=%d&fmt=json",
+		mbBaseURL, url.QueryEscape(artistID), mbBrowseLimit, offset)
+	var resp mbRecordingBrowse
+	if err := c.get(u, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
 }
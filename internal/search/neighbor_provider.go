@@ -7,53 +7,157 @@ import (
 	sixdegrees "github.com/Jonnymurillo288/MelodyMap/sixDegrees"
 )
 
-type MusicBrainzSearcher interface {
-	SearchArtist(name string) ([]mbArtist, error)
+// GetEdgeTracks returns the tracks linking an artist to one specific
+// neighbor, with the same evidence the BFS neighbor query collects.
+func (s *Store) GetEdgeTracks(
+	ctx context.Context,
+	a *sixdegrees.Artists,
+	neighborMBID string,
+	limit int,
+) ([]TrackWrapper, error) {
+
+	if a == nil || a.ID == "" || neighborMBID == "" {
+		return nil, fmt.Errorf("artist missing MBID")
+	}
+	if limit <= 0 {
+		limit = 200
+	}
+
+	edges, err := s.neighborEdges(ctx, a, []string{neighborMBID}, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(edges) == 0 {
+		return nil, nil
+	}
+	return edges[0].Track, nil
 }
 
-func (s *Store) MusicBrainzNeighborProvider(
+// GetEdgeTracksBatch is GetEdgeTracks for several neighbors at once, in
+// one query, keyed by neighbor MBID. limit applies per neighbor.
+func (s *Store) GetEdgeTracksBatch(
+	ctx context.Context,
 	a *sixdegrees.Artists,
+	neighborMBIDs []string,
 	limit int,
-	verbose bool,
-) ([]*NeighborEdge, int, error) {
+) (map[string][]TrackWrapper, error) {
 
+	out := make(map[string][]TrackWrapper, len(neighborMBIDs))
 	if a == nil || a.ID == "" {
-		return nil, 400, fmt.Errorf("artist missing MBID")
+		return nil, fmt.Errorf("artist missing MBID")
+	}
+	if len(neighborMBIDs) == 0 {
+		return out, nil
 	}
-
 	if limit <= 0 {
 		limit = 200
 	}
 
+	edges, err := s.neighborEdges(ctx, a, neighborMBIDs, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range edges {
+		out[e.Artist.ID] = e.Track
+	}
+	return out, nil
+}
+
+// neighborEdges runs the collaboration query for an artist, restricted to
+// the given neighbor MBIDs, and groups rows by neighbor. limit caps the
+// rows read per neighbor.
+func (s *Store) neighborEdges(
+	ctx context.Context,
+	a *sixdegrees.Artists,
+	neighborMBIDs []string,
+	limit int,
+) ([]*NeighborEdge, error) {
+
 	// Make sure to return the variables below
-	// nbID, nbName, recID, recName, trackID, trackName, releaseID
+	// nbID, nbName, recID, recName, trackID, trackName, releaseID,
+	// releaseTitle, releaseGroupID, releaseYear, releaseType,
+	// creditedAs (input artist), nbCreditedAs
 	new_q := `
 		WITH input_artist AS (
-			SELECT id
-			FROM artist
-			WHERE gid = $1
+			SELECT ` + artistIDByGID("$1") + ` AS id
+		),
+		wanted AS (
+			SELECT ` + artistIDByGID("n.gid") + ` AS id
+			FROM unnest($3::uuid[]) AS n(gid)
+		),
+		-- cap the rows per neighbor before the per-row lookups below
+		ranked AS (
+			SELECT
+				c.artist_id,
+				c.neighbor_artist_id,
+				c.recording_id,
+				t.id AS track_id,
+				row_number() OVER (
+					PARTITION BY c.neighbor_artist_id
+					ORDER BY c.recording_id, t.id
+				) AS rn
+			FROM artist_collab c
+			JOIN input_artist ia ON ia.id = c.artist_id
+			JOIN track t         ON t.recording = c.recording_id
+			WHERE c.neighbor_artist_id IN (SELECT id FROM wanted)
 		)
-		SELECT 
+		SELECT
 			a2.gid::text,
 			a2.name,
 			r.gid::text,
 			r.name,
 			t.gid::text,
 			t.name,
-			rl.gid::text
-		FROM artist_collab c
-		JOIN input_artist ia       ON ia.id = c.artist_id
+			rl.gid::text,
+			rl.name,
+			rg.gid::text,
+			COALESCE((
+				SELECT min(d.date_year)
+				FROM (
+					SELECT date_year FROM release_country WHERE release = rl.id
+					UNION ALL
+					SELECT date_year FROM release_unknown_country WHERE release = rl.id
+				) d
+			), 0),
+			CASE
+				WHEN EXISTS (
+					SELECT 1
+					FROM release_group_secondary_type_join j
+					JOIN release_group_secondary_type st ON st.id = j.secondary_type
+					WHERE j.release_group = rg.id AND st.name = 'Compilation'
+				) THEN 'compilation'
+				WHEN EXISTS (
+					SELECT 1
+					FROM release_group_secondary_type_join j
+					JOIN release_group_secondary_type st ON st.id = j.secondary_type
+					WHERE j.release_group = rg.id AND st.name = 'Live'
+				) THEN 'live'
+				ELSE lower(COALESCE(pt.name, ''))
+			END,
+			COALESCE((
+				SELECT acn.name FROM artist_credit_name acn
+				WHERE acn.artist_credit = t.artist_credit AND acn.artist = c.artist_id
+				LIMIT 1
+			), ''),
+			COALESCE((
+				SELECT acn.name FROM artist_credit_name acn
+				WHERE acn.artist_credit = t.artist_credit AND acn.artist = c.neighbor_artist_id
+				LIMIT 1
+			), '')
+		FROM ranked c
 		JOIN recording r           ON r.id = c.recording_id
-		JOIN track t               ON t.recording = r.id
+		JOIN track t               ON t.id = c.track_id
 		JOIN medium m              ON m.id = t.medium
 		JOIN release rl            ON rl.id = m.release
+		JOIN release_group rg      ON rg.id = rl.release_group
+		LEFT JOIN release_group_primary_type pt ON pt.id = rg.type
 		JOIN artist a2             ON a2.id = c.neighbor_artist_id
-		LIMIT $2;
+		WHERE c.rn <= $2;
 	`
 
-	rows, err := s.DB.QueryContext(context.Background(), new_q, a.ID, limit)
+	rows, err := s.DB.QueryContext(ctx, new_q, a.ID, limit, neighborMBIDs)
 	if err != nil {
-		return nil, 500, err
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -67,19 +171,23 @@ func (s *Store) MusicBrainzNeighborProvider(
 		var nbID, nbName string
 		var recID, recName string
 		var trackID, trackName string
-		var releaseID string
+		var releaseID, releaseTitle, releaseGroupID string
+		var releaseYear int
+		var releaseType string
+		var creditedAs, nbCreditedAs string
 
 		if err := rows.Scan(
 			&nbID, &nbName,
 			&recID, &recName,
 			&trackID, &trackName,
-			&releaseID,
+			&releaseID, &releaseTitle, &releaseGroupID,
+			&releaseYear, &releaseType,
+			&creditedAs, &nbCreditedAs,
 		); err != nil {
-			return nil, 500, err
+			return nil, err
 		}
 		if trackID == "" {
-			fmt.Println("Skipping track with empty ID for neighbor artist:", nbName)
-			continue
+			continue // no track to show as evidence
 		}
 
 		// if neighbor not seen before, create struct
@@ -98,11 +206,16 @@ func (s *Store) MusicBrainzNeighborProvider(
 		// correct dedupe: per-neighbor
 		if !visitedByNeighbor[nbID][trackID] {
 			grouped[nbID].Track = append(grouped[nbID].Track, TrackWrapper{
-				ID:            trackID,
-				Name:          trackName,
-				RecordingID:   recID,
-				RecordingName: recName,
-				PhotoURL:      "https://coverartarchive.org/release/" + releaseID + "/front",
+				ID:             trackID,
+				Name:           trackName,
+				RecordingID:    recID,
+				RecordingName:  recName,
+				PhotoURL:       "https://coverartarchive.org/release/" + releaseID + "/front",
+				ReleaseTitle:   releaseTitle,
+				ReleaseGroupID: releaseGroupID,
+				ReleaseYear:    releaseYear,
+				ReleaseType:    releaseType,
+				Credits:        hopCredits(a, creditedAs, nbID, nbName, nbCreditedAs),
 			})
 			visitedByNeighbor[nbID][trackID] = true
 		}
@@ -113,5 +226,19 @@ func (s *Store) MusicBrainzNeighborProvider(
 		out = append(out, v)
 	}
 
-	return out, 200, nil
+	return out, rows.Err()
+}
+
+// hopCredits records how both ends of a hop were credited on a track.
+// Artists missing from the track's own credit (e.g. credited only on the
+// recording) are left out.
+func hopCredits(a *sixdegrees.Artists, creditedAs, nbID, nbName, nbCreditedAs string) []CreditInfo {
+	var out []CreditInfo
+	if creditedAs != "" {
+		out = append(out, CreditInfo{ArtistID: a.ID, ArtistName: a.Name, CreditedAs: creditedAs})
+	}
+	if nbCreditedAs != "" {
+		out = append(out, CreditInfo{ArtistID: nbID, ArtistName: nbName, CreditedAs: nbCreditedAs})
+	}
+	return out
 }
@@ -0,0 +1,371 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+//
+// ========================================================================
+// Top-artist pair matrix (written by cmd/pairmatrix)
+// ========================================================================
+//
+
+// pairTracksPerHop caps the evidence loaded when a matrix path is turned
+// into a full search response.
+const pairTracksPerHop = 50
+
+// TopArtist is one resolved entry of static/top_artists.txt.
+type TopArtist struct {
+	Rank int
+	ID   int32
+	MBID string
+	Name string
+}
+
+// PairDistance is one unordered pair of top artists. Distance is -1 when
+// no path exists; Path holds internal artist ids from StartID to TargetID.
+type PairDistance struct {
+	StartID  int32
+	TargetID int32
+	Distance int
+	Path     []int32
+}
+
+// PairAnswer is a precomputed pair as served by the API.
+type PairAnswer struct {
+	Start       ArtistRef   `json:"start"`
+	Target      ArtistRef   `json:"target"`
+	Reachable   bool        `json:"reachable"`
+	Distance    int         `json:"distance"`
+	Path        []ArtistRef `json:"path"`
+	DataVersion int64       `json:"dataVersion"`
+	ComputedAt  time.Time   `json:"computedAt"`
+}
+
+// ResolveTopArtists maps names to artists through ResolveArtist, with
+// the same acceptance rule as ResolveArtistOnce, so homonyms resolve to
+// the artist searches would pick. List order is kept as rank; names that
+// match nothing are returned separately.
+func (s *Store) ResolveTopArtists(ctx context.Context, names []string) ([]TopArtist, []string, error) {
+	var out []TopArtist
+	var missing []string
+	seen := make(map[int]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		res, err := s.ResolveArtist(ctx, name, 1)
+		if err != nil {
+			return nil, nil, fmt.Errorf("resolve %q: %w", name, err)
+		}
+		c := res.Chosen
+		if c == nil || c.NameScore < ResolveAcceptNameScore {
+			missing = append(missing, name)
+			continue
+		}
+		if seen[c.ID] {
+			continue
+		}
+		seen[c.ID] = true
+		out = append(out, TopArtist{Rank: len(out) + 1, ID: int32(c.ID), MBID: c.MBID, Name: c.Name})
+	}
+	return out, missing, nil
+}
+
+// SavePairMatrix replaces the top-artist list and pair matrix in one
+// transaction. fill streams pairs through emit, which batches them into
+// unnest() inserts; pairs are stored with start_id < target_id.
+func (s *Store) SavePairMatrix(
+	ctx context.Context,
+	top []TopArtist,
+	dataVersion int64,
+	fill func(emit func(PairDistance) error) error,
+) error {
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `TRUNCATE top_artist, artist_pair_distance;`); err != nil {
+		return err
+	}
+
+	ids := make([]int32, len(top))
+	ranks := make([]int32, len(top))
+	names := make([]string, len(top))
+	for i, a := range top {
+		ids[i], ranks[i], names[i] = a.ID, int32(a.Rank), a.Name
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO top_artist (artist_id, rank, name)
+		SELECT * FROM unnest($1::int[], $2::int[], $3::text[])
+		ON CONFLICT DO NOTHING;
+	`, ids, ranks, names)
+	if err != nil {
+		return err
+	}
+
+	var starts, targets, dists []int32
+	var paths []string
+
+	flush := func() error {
+		if len(starts) == 0 {
+			return nil
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO artist_pair_distance (start_id, target_id, distance, path, data_version)
+			SELECT s, t, NULLIF(d, -1), p::int[], $5
+			FROM unnest($1::int[], $2::int[], $3::int[], $4::text[]) AS u(s, t, d, p)
+			ON CONFLICT DO NOTHING;
+		`, starts, targets, dists, paths, dataVersion)
+		starts, targets, dists, paths = starts[:0], targets[:0], dists[:0], paths[:0]
+		return err
+	}
+
+	err = fill(func(p PairDistance) error {
+		if p.StartID == p.TargetID {
+			return nil
+		}
+		if p.StartID > p.TargetID {
+			p.StartID, p.TargetID = p.TargetID, p.StartID
+			p.Path = reversedIDs(p.Path)
+		}
+		starts = append(starts, p.StartID)
+		targets = append(targets, p.TargetID)
+		dists = append(dists, int32(p.Distance))
+		paths = append(paths, intArrayLiteral(p.Path))
+		if len(starts) >= analyticsWriteBatch {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// pairsCurrent restricts artist_pair_distance p to rows computed at the
+// current data version.
+const pairsCurrent = `
+	JOIN melodymap_meta m
+	  ON m.key = 'data_version' AND m.value = p.data_version
+`
+
+// HardestPairs returns the reachable top-artist pairs that are farthest apart.
+func (s *Store) HardestPairs(ctx context.Context, limit int) ([]PairAnswer, error) {
+	return s.queryPairs(ctx, `
+		SELECT p.start_id, p.target_id, p.distance, p.path, p.data_version, p.computed_at
+		FROM artist_pair_distance p
+		`+pairsCurrent+`
+		WHERE p.distance IS NOT NULL
+		ORDER BY p.distance DESC, p.start_id, p.target_id
+		LIMIT $1;
+	`, limit)
+}
+
+// CountPairsInBand returns how many reachable pairs of the current data
+// version have a distance in [lo, hi].
+func (s *Store) CountPairsInBand(ctx context.Context, lo, hi int) (int, error) {
+	var n int
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT count(*)
+		FROM artist_pair_distance p
+		`+pairsCurrent+`
+		WHERE p.distance BETWEEN $1 AND $2;
+	`, lo, hi).Scan(&n)
+	return n, err
+}
+
+// PairInBand returns the i-th reachable pair with a distance in [lo, hi],
+// in a stable order, so callers can pick among them deterministically.
+func (s *Store) PairInBand(ctx context.Context, lo, hi, i int) (*PairAnswer, bool, error) {
+	pairs, err := s.queryPairs(ctx, `
+		SELECT p.start_id, p.target_id, p.distance, p.path, p.data_version, p.computed_at
+		FROM artist_pair_distance p
+		`+pairsCurrent+`
+		WHERE p.distance BETWEEN $1 AND $2
+		ORDER BY p.start_id, p.target_id
+		OFFSET $3
+		LIMIT 1;
+	`, lo, hi, i)
+	if err != nil || len(pairs) == 0 {
+		return nil, false, err
+	}
+	return &pairs[0], true, nil
+}
+
+// LookupPair returns the precomputed answer for two artist MBIDs in the
+// requested direction, or false when the pair is not in the matrix or was
+// computed against an older data version.
+func (s *Store) LookupPair(ctx context.Context, startMBID, targetMBID string) (*PairAnswer, bool, error) {
+	// a redirected start MBID would never match the stored refs below
+	startMBID, err := s.CanonicalMBID(ctx, startMBID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	pairs, err := s.queryPairs(ctx, `
+		WITH ids AS (
+			SELECT
+				`+artistIDByGID("$1")+` AS a,
+				`+artistIDByGID("$2")+` AS b
+		)
+		SELECT p.start_id, p.target_id, p.distance, p.path, p.data_version, p.computed_at
+		FROM artist_pair_distance p
+		CROSS JOIN ids
+		`+pairsCurrent+`
+		WHERE p.start_id = LEAST(ids.a, ids.b)
+		  AND p.target_id = GREATEST(ids.a, ids.b);
+	`, startMBID, targetMBID)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(pairs) == 0 {
+		return nil, false, nil
+	}
+
+	p := pairs[0]
+	if p.Start.MBID != startMBID {
+		p.Start, p.Target = p.Target, p.Start
+		p.Path = reversedRefs(p.Path)
+	}
+	return &p, true, nil
+}
+
+func (s *Store) queryPairs(ctx context.Context, q string, args ...any) ([]PairAnswer, error) {
+	rows, err := s.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type rawPair struct {
+		start, target int
+		distance      sql.NullInt32
+		path          []int
+		version       int64
+		computed      time.Time
+	}
+
+	m := pgtype.NewMap()
+	var raw []rawPair
+	var ids []int
+	for rows.Next() {
+		var r rawPair
+		if err := rows.Scan(&r.start, &r.target, &r.distance, m.SQLScanner(&r.path), &r.version, &r.computed); err != nil {
+			return nil, err
+		}
+		raw = append(raw, r)
+		ids = append(ids, r.start, r.target)
+		ids = append(ids, r.path...)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	refs, err := s.ArtistRefsByID(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]PairAnswer, 0, len(raw))
+	for _, r := range raw {
+		p := PairAnswer{
+			Start:       refs[r.start],
+			Target:      refs[r.target],
+			Reachable:   r.distance.Valid,
+			Distance:    -1,
+			Path:        make([]ArtistRef, 0, len(r.path)),
+			DataVersion: r.version,
+			ComputedAt:  r.computed,
+		}
+		if r.distance.Valid {
+			p.Distance = int(r.distance.Int32)
+		}
+		for _, id := range r.path {
+			p.Path = append(p.Path, refs[id])
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// StepsForPath loads hop evidence for a chain of artists and turns it into
+// the Step list a search response carries.
+func (s *Store) StepsForPath(ctx context.Context, path []ArtistRef, tracksPerHop int) ([]Step, error) {
+	return stepsForPath(ctx, s, path, tracksPerHop)
+}
+
+// pairSearchResponse answers a shortest-path request from the pair matrix
+// and returns the data version the answer was computed at. ok is false
+// when the pair is unknown, stale, unreachable or deeper than asked.
+func (s *Store) pairSearchResponse(ctx context.Context, req SearchRequest, startMBID, targetMBID string) (*SearchResponse, int64, bool, error) {
+	if req.Mode != "" && req.Mode != ModeShortest {
+		return nil, 0, false, nil
+	}
+
+	p, ok, err := s.LookupPair(ctx, startMBID, targetMBID)
+	if err != nil || !ok || !p.Reachable {
+		return nil, 0, false, err
+	}
+	if req.Depth > 0 && p.Distance > req.Depth {
+		return nil, 0, false, nil
+	}
+
+	steps, err := s.StepsForPath(ctx, p.Path, pairTracksPerHop)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	return &SearchResponse{
+		Start:  req.Start,
+		Target: req.Target,
+		Hops:   len(steps),
+		Path:   steps,
+		Status: 200,
+	}, p.DataVersion, true, nil
+}
+
+func intArrayLiteral(ids []int32) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, id := range ids {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.Itoa(int(id)))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func reversedIDs(in []int32) []int32 {
+	out := make([]int32, len(in))
+	for i, v := range in {
+		out[len(in)-1-i] = v
+	}
+	return out
+}
+
+func reversedRefs(in []ArtistRef) []ArtistRef {
+	out := make([]ArtistRef, len(in))
+	for i, v := range in {
+		out[len(in)-1-i] = v
+	}
+	return out
+}
@@ -0,0 +1,285 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+//
+// ========================================================================
+// Persistent path cache
+// ========================================================================
+//
+// Finished searches are stored keyed by (start MBID, target MBID,
+// options, data version). Collaboration paths are undirected, so one row
+// answers both directions. Migrate bumps the data version whenever it
+// rebuilds the collaboration tables, which retires every older entry.
+
+const pathCacheTimeout = 5 * time.Second
+
+// migratePathCache creates the metadata and cache tables.
+func (s *Store) migratePathCache(ctx context.Context) error {
+	q := `
+		CREATE TABLE IF NOT EXISTS melodymap_meta (
+			key TEXT PRIMARY KEY,
+			value BIGINT NOT NULL
+		);
+
+		INSERT INTO melodymap_meta (key, value)
+		VALUES ('data_version', 1)
+		ON CONFLICT (key) DO NOTHING;
+
+		CREATE TABLE IF NOT EXISTS path_cache (
+			start_mbid TEXT NOT NULL,
+			target_mbid TEXT NOT NULL,
+			options TEXT NOT NULL,
+			data_version BIGINT NOT NULL,
+			response JSONB NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			PRIMARY KEY (start_mbid, target_mbid, options, data_version)
+		);
+	`
+	_, err := s.DB.ExecContext(ctx, q)
+	return err
+}
+
+// DataVersion returns the version of the collaboration data currently
+// loaded. Anything derived from an older version is stale.
+func (s *Store) DataVersion(ctx context.Context) (int64, error) {
+	var v int64
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT value FROM melodymap_meta WHERE key = 'data_version';
+	`).Scan(&v)
+	return v, err
+}
+
+// BumpDataVersion marks the collaboration data as rebuilt and drops
+// cached paths computed against earlier versions.
+func (s *Store) BumpDataVersion(ctx context.Context) (int64, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	v, err := bumpDataVersion(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	return v, tx.Commit()
+}
+
+// bumpDataVersion is BumpDataVersion inside the caller's transaction, so
+// the version moves together with the data it describes.
+func bumpDataVersion(ctx context.Context, tx *sql.Tx) (int64, error) {
+	var v int64
+	err := tx.QueryRowContext(ctx, `
+		UPDATE melodymap_meta SET value = value + 1
+		WHERE key = 'data_version'
+		RETURNING value;
+	`).Scan(&v)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM path_cache WHERE data_version < $1;`, v); err != nil {
+		return 0, err
+	}
+	return v, nil
+}
+
+// PathCacheOptions is the options part of the cache key for a request.
+// Popularity weights are relative to the target, so those entries are
+// only served in the direction they were computed (see GetCachedPath).
+func PathCacheOptions(req SearchRequest) string {
+	mode := req.Mode
+	if mode == "" {
+		mode = ModeShortest
+	}
+	if mode == ModeWeighted {
+		strategy := req.Strategy
+		if strategy == "" {
+			strategy = StrategyStrength
+		}
+		return fmt.Sprintf("mode=%s;strategy=%s;depth=%d", mode, strategy, req.Depth)
+	}
+	return fmt.Sprintf("mode=%s;depth=%d", mode, req.Depth)
+}
+
+// GetCachedPath returns the cached response for a pair at the current
+// data version, in either direction unless the options are directed.
+// Reverse hits are flipped so the path always runs from startMBID to
+// targetMBID.
+func (s *Store) GetCachedPath(ctx context.Context, startMBID, targetMBID, options string) (*SearchResponse, bool, error) {
+	q := `
+		SELECT pc.start_mbid, pc.response
+		FROM path_cache pc
+		JOIN melodymap_meta m
+		  ON m.key = 'data_version' AND m.value = pc.data_version
+		WHERE pc.options = $3
+		  AND ((pc.start_mbid = $1 AND pc.target_mbid = $2)
+		    OR ($4 AND pc.start_mbid = $2 AND pc.target_mbid = $1))
+		ORDER BY pc.start_mbid = $1 DESC
+		LIMIT 1;
+	`
+
+	var storedStart string
+	var raw []byte
+	err := s.DB.QueryRowContext(ctx, q, startMBID, targetMBID, options, !directedPathCache(options)).Scan(&storedStart, &raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var resp SearchResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, false, err
+	}
+	if storedStart != startMBID {
+		resp = ReversePath(resp)
+	}
+	return &resp, true, nil
+}
+
+// directedPathCache reports whether cached paths under options depend on
+// which end is the target.
+func directedPathCache(options string) bool {
+	return strings.Contains(options, ";strategy="+StrategyPopularity+";")
+}
+
+// PutCachedPath stores a finished response at the current data version.
+func (s *Store) PutCachedPath(ctx context.Context, startMBID, targetMBID, options string, resp SearchResponse) error {
+	return s.putCachedPathAt(ctx, startMBID, targetMBID, options, 0, resp)
+}
+
+// putCachedPathAt stores a response computed at data version v, and only
+// while v is still current; v = 0 means the current version.
+func (s *Store) putCachedPathAt(ctx context.Context, startMBID, targetMBID, options string, v int64, resp SearchResponse) error {
+	raw, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.DB.ExecContext(ctx, `
+		INSERT INTO path_cache (start_mbid, target_mbid, options, data_version, response)
+		SELECT $1, $2, $3, value, $4
+		FROM melodymap_meta
+		WHERE key = 'data_version'
+		  AND ($5::bigint = 0 OR value = $5::bigint)
+		ON CONFLICT (start_mbid, target_mbid, options, data_version)
+		DO UPDATE SET response = EXCLUDED.response, created_at = now();
+	`, startMBID, targetMBID, options, raw, v)
+	return err
+}
+
+// ReversePath flips a response so it runs from its target to its start.
+// Tracks are shared by both ends of a hop, so they stay as they are.
+func ReversePath(resp SearchResponse) SearchResponse {
+	out := resp
+	out.Start, out.Target = resp.Target, resp.Start
+
+	out.Path = make([]Step, len(resp.Path))
+	for i, st := range resp.Path {
+		st.From, st.To = st.To, st.From
+		st.FromID, st.ToID = st.ToID, st.FromID
+		out.Path[len(resp.Path)-1-i] = st
+	}
+	return out
+}
+
+// CachedSearch answers a search request from the path cache when the
+// pair has already been solved at the current data version, or from the
+// top-artist pair matrix. Failures are treated as misses; the caller
+// falls back to a live search. Engines over other graph sources have no
+// cache and always miss.
+func (e *Engine) CachedSearch(ctx context.Context, req SearchRequest) (*SearchResponse, bool) {
+	s := e.store
+	if s == nil {
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pathCacheTimeout)
+	defer cancel()
+
+	startKey, targetKey := req.Endpoints()
+	start, err := s.resolveEndpoint(ctx, startKey)
+	if err != nil {
+		return nil, false
+	}
+	target, err := s.resolveEndpoint(ctx, targetKey)
+	if err != nil {
+		return nil, false
+	}
+
+	options := PathCacheOptions(req)
+	resp, ok, err := s.GetCachedPath(ctx, start.ID, target.ID, options)
+	if err != nil {
+		log.Printf("[PATH CACHE] lookup failed: %v", err)
+		return nil, false
+	}
+	if ok {
+		resp.Start, resp.Target = req.Start, req.Target
+		return resp, true
+	}
+
+	// top-artist pairs are precomputed by cmd/pairmatrix
+	resp, version, ok, err := s.pairSearchResponse(ctx, req, start.ID, target.ID)
+	if err != nil {
+		log.Printf("[PATH CACHE] pair matrix lookup failed: %v", err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+	if err := s.putCachedPathAt(ctx, start.ID, target.ID, options, version, *resp); err != nil {
+		log.Printf("[PATH CACHE] store failed: %v", err)
+	}
+	return resp, true
+}
+
+// cacheVersion returns the data version a search is about to run
+// against, or 0 when results cannot be cached.
+func (e *Engine) cacheVersion() int64 {
+	if e.store == nil {
+		return 0
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pathCacheTimeout)
+	defer cancel()
+
+	v, err := e.store.DataVersion(ctx)
+	if err != nil {
+		log.Printf("[PATH CACHE] version lookup failed: %v", err)
+		return 0
+	}
+	return v
+}
+
+// storeCachedPath records a successful search computed at data version v,
+// unless the data has been rebuilt since. Errors are only logged: the
+// search result is already delivered either way.
+func (e *Engine) storeCachedPath(req SearchRequest, v int64, resp SearchResponse) {
+	s := e.store
+	if s == nil || v == 0 || len(resp.Path) == 0 {
+		return
+	}
+	startMBID := resp.Path[0].FromID
+	targetMBID := resp.Path[len(resp.Path)-1].ToID
+	if startMBID == "" || targetMBID == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pathCacheTimeout)
+	defer cancel()
+
+	if err := s.putCachedPathAt(ctx, startMBID, targetMBID, PathCacheOptions(req), v, resp); err != nil {
+		log.Printf("[PATH CACHE] store failed: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package search
+
+import "testing"
+
+func TestReversePath(t *testing.T) {
+	resp := SearchResponse{
+		Start:  "A",
+		Target: "C",
+		Hops:   2,
+		Path: []Step{
+			{From: "A", To: "B", FromID: "a", ToID: "b", Tracks: []TrackInfo{{ID: "t1"}}},
+			{From: "B", To: "C", FromID: "b", ToID: "c", Tracks: []TrackInfo{{ID: "t2"}}},
+		},
+		Status: 200,
+	}
+
+	got := ReversePath(resp)
+
+	if got.Start != "C" || got.Target != "A" || got.Hops != 2 {
+		t.Fatalf("unexpected header: %+v", got)
+	}
+	want := [][4]string{{"C", "B", "c", "b"}, {"B", "A", "b", "a"}}
+	for i, w := range want {
+		st := got.Path[i]
+		if st.From != w[0] || st.To != w[1] || st.FromID != w[2] || st.ToID != w[3] {
+			t.Fatalf("step %d: got %+v", i, st)
+		}
+	}
+	if got.Path[0].Tracks[0].ID != "t2" {
+		t.Fatalf("tracks should travel with their hop")
+	}
+
+	// the input must not be modified
+	if resp.Path[0].From != "A" {
+		t.Fatalf("ReversePath mutated its input")
+	}
+}
+
+func TestPathCacheOptions_DefaultMode(t *testing.T) {
+	a := PathCacheOptions(SearchRequest{Depth: 4})
+	b := PathCacheOptions(SearchRequest{Depth: 4, Mode: ModeShortest})
+	if a != b {
+		t.Fatalf("empty mode should key like shortest: %q vs %q", a, b)
+	}
+	if a == PathCacheOptions(SearchRequest{Depth: 4, Mode: ModeWidest}) {
+		t.Fatalf("modes must not share cache entries")
+	}
+}
+
+func TestDirectedPathCache(t *testing.T) {
+	if directedPathCache(PathCacheOptions(SearchRequest{Depth: 4, Mode: ModeWeighted})) {
+		t.Fatalf("strength weights are symmetric and may serve reverse hits")
+	}
+	if !directedPathCache(PathCacheOptions(SearchRequest{Depth: 4, Mode: ModeWeighted, Strategy: StrategyPopularity})) {
+		t.Fatalf("popularity weights depend on the target and must not serve reverse hits")
+	}
+}
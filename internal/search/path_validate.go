@@ -0,0 +1,243 @@
+package search
+
+import (
+	"context"
+)
+
+//
+// ========================================================================
+// Path validation
+// ========================================================================
+//
+
+const (
+	// ValidateMaxChain bounds the number of artists in a checked chain.
+	ValidateMaxChain = 20
+	// bridgeMaxDepth and bridgeMaxVisited bound the search for a fix.
+	bridgeMaxDepth   = 4
+	bridgeMaxVisited = 20000
+	// validateTracksPerHop caps the evidence returned per valid hop.
+	validateTracksPerHop = 20
+)
+
+// HopCheck is the verdict on one consecutive pair of a chain.
+type HopCheck struct {
+	From   ArtistRef   `json:"from"`
+	To     ArtistRef   `json:"to"`
+	Valid  bool        `json:"valid"`
+	Tracks []TrackInfo `json:"tracks"`
+}
+
+// PathFix replaces the first broken hop with the shortest bridge found
+// between its two artists. Chain is the full corrected chain.
+type PathFix struct {
+	Hop    int         `json:"hop"`
+	Bridge []ArtistRef `json:"bridge"`
+	Chain  []ArtistRef `json:"chain"`
+}
+
+// PathValidation is the result of checking a user-supplied chain.
+type PathValidation struct {
+	Valid     bool        `json:"valid"`
+	Chain     []ArtistRef `json:"chain"`
+	Hops      []HopCheck  `json:"hops"`
+	BrokenHop *int        `json:"brokenHop,omitempty"`
+	Fix       *PathFix    `json:"fix,omitempty"`
+}
+
+// ValidatePath resolves a chain of artist names or MBIDs and checks every
+// hop against artist_collab, attaching evidence tracks to valid hops. For
+// an invalid chain it points at the first broken hop and, when one exists
+// within bridgeMaxDepth, suggests the shortest bridge for it. Entries
+// that match no artist yield an *UnknownArtistError.
+func (s *Store) ValidatePath(ctx context.Context, entries []string) (*PathValidation, error) {
+	chain, err := s.resolveChain(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(chain))
+	refs := make([]ArtistRef, len(chain))
+	for i, a := range chain {
+		ids[i] = a.ID
+		refs[i] = ArtistRef{MBID: a.MBID, Name: a.Name}
+	}
+
+	broken, err := s.MissingHops(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	isBroken := make(map[int]bool, len(broken))
+	for _, h := range broken {
+		isBroken[h] = true
+	}
+
+	out := &PathValidation{
+		Valid: len(broken) == 0 && len(chain) >= 2,
+		Chain: refs,
+		Hops:  make([]HopCheck, 0, max(len(chain)-1, 0)),
+	}
+
+	for i := 1; i < len(chain); i++ {
+		hc := HopCheck{From: refs[i-1], To: refs[i], Valid: !isBroken[i-1], Tracks: []TrackInfo{}}
+		if hc.Valid {
+			steps, err := s.StepsForPath(ctx, refs[i-1:i+1], validateTracksPerHop)
+			if err != nil {
+				return nil, err
+			}
+			if len(steps) == 1 && steps[0].Tracks != nil {
+				hc.Tracks = steps[0].Tracks
+			}
+		}
+		out.Hops = append(out.Hops, hc)
+	}
+
+	if len(broken) == 0 {
+		return out, nil
+	}
+
+	first := broken[0]
+	out.BrokenHop = &first
+
+	bridge, err := bidirectionalPath(ids[first], ids[first+1], bridgeMaxDepth, bridgeMaxVisited,
+		func(frontier []int) (map[int][]int, error) {
+			return s.NeighborIDsBatch(ctx, frontier)
+		})
+	if err != nil {
+		return nil, err
+	}
+	if bridge == nil {
+		return out, nil
+	}
+
+	bridgeRefs, err := s.ArtistRefsByID(ctx, bridge)
+	if err != nil {
+		return nil, err
+	}
+	fix := &PathFix{Hop: first}
+	for _, id := range bridge {
+		fix.Bridge = append(fix.Bridge, bridgeRefs[id])
+	}
+	fix.Chain = append(fix.Chain, refs[:first]...)
+	fix.Chain = append(fix.Chain, fix.Bridge...)
+	fix.Chain = append(fix.Chain, refs[first+2:]...)
+	out.Fix = fix
+
+	return out, nil
+}
+
+// MissingHops returns the index of every consecutive pair in ids that
+// never shared a recording in artist_collab, in order.
+func (s *Store) MissingHops(ctx context.Context, ids []int) ([]int, error) {
+	if len(ids) < 2 {
+		return nil, nil
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT u.i - 1
+		FROM unnest($1::int[], $2::int[]) WITH ORDINALITY AS u(a, b, i)
+		WHERE NOT EXISTS (
+			SELECT 1 FROM artist_collab ac
+			WHERE ac.artist_id = u.a AND ac.neighbor_artist_id = u.b
+		)
+		ORDER BY u.i;
+	`, ids[:len(ids)-1], ids[1:])
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []int
+	for rows.Next() {
+		var h int
+		if err := rows.Scan(&h); err != nil {
+			return nil, err
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+// bidirectionalPath finds a shortest path between src and dst by growing
+// BFS frontiers from both ends, always expanding the smaller one. It
+// gives up (returning nil) after maxDepth total hops or once more than
+// maxVisited artists have been seen. expand returns neighbor lists for a
+// whole frontier at once.
+func bidirectionalPath(
+	src, dst, maxDepth, maxVisited int,
+	expand func(frontier []int) (map[int][]int, error),
+) ([]int, error) {
+
+	if src == dst {
+		return []int{src}, nil
+	}
+
+	parentF := map[int]int{src: src}
+	parentB := map[int]int{dst: dst}
+	distF := map[int]int{src: 0}
+	distB := map[int]int{dst: 0}
+	frontierF := []int{src}
+	frontierB := []int{dst}
+
+	for depth := 0; depth < maxDepth; depth++ {
+		if len(frontierF) == 0 || len(frontierB) == 0 {
+			return nil, nil
+		}
+
+		forward := len(frontierF) <= len(frontierB)
+		frontier, parent, dist, otherDist := frontierF, parentF, distF, distB
+		if !forward {
+			frontier, parent, dist, otherDist = frontierB, parentB, distB, distF
+		}
+
+		nbs, err := expand(frontier)
+		if err != nil {
+			return nil, err
+		}
+
+		// every meeting found in this level is a candidate; the shortest wins
+		meet, best := -1, -1
+		var next []int
+		for _, v := range frontier {
+			for _, nb := range nbs[v] {
+				if _, seen := parent[nb]; seen {
+					continue
+				}
+				parent[nb] = v
+				dist[nb] = dist[v] + 1
+				next = append(next, nb)
+				if d, ok := otherDist[nb]; ok && (best < 0 || dist[nb]+d < best) {
+					meet, best = nb, dist[nb]+d
+				}
+			}
+		}
+
+		if meet >= 0 {
+			var path []int
+			for v := meet; ; v = parentF[v] {
+				path = append(path, v)
+				if v == src {
+					break
+				}
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			for v := meet; v != dst; {
+				v = parentB[v]
+				path = append(path, v)
+			}
+			return path, nil
+		}
+
+		if forward {
+			frontierF = next
+		} else {
+			frontierB = next
+		}
+		if len(parentF)+len(parentB) > maxVisited {
+			return nil, nil
+		}
+	}
+	return nil, nil
+}
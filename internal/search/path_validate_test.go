@@ -0,0 +1,66 @@
+package search
+
+import "testing"
+
+// fixtureExpand serves neighbor lists for an undirected graph given as edges.
+func fixtureExpand(edges [][2]int) func([]int) (map[int][]int, error) {
+	adj := make(map[int][]int)
+	for _, e := range edges {
+		adj[e[0]] = append(adj[e[0]], e[1])
+		adj[e[1]] = append(adj[e[1]], e[0])
+	}
+	return func(frontier []int) (map[int][]int, error) {
+		out := make(map[int][]int, len(frontier))
+		for _, v := range frontier {
+			out[v] = adj[v]
+		}
+		return out, nil
+	}
+}
+
+func TestBidirectionalPath_Shortest(t *testing.T) {
+	// long way 1-2-3-4-5, shortcut 1-6-5
+	expand := fixtureExpand([][2]int{{1, 2}, {2, 3}, {3, 4}, {4, 5}, {1, 6}, {6, 5}})
+
+	path, err := bidirectionalPath(1, 5, 4, 1000, expand)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int{1, 6, 5}
+	if len(path) != len(want) {
+		t.Fatalf("want %v, got %v", want, path)
+	}
+	for i := range want {
+		if path[i] != want[i] {
+			t.Fatalf("want %v, got %v", want, path)
+		}
+	}
+}
+
+func TestBidirectionalPath_OddLength(t *testing.T) {
+	expand := fixtureExpand([][2]int{{1, 2}, {2, 3}, {3, 4}})
+
+	path, err := bidirectionalPath(1, 4, 4, 1000, expand)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(path) != 4 || path[0] != 1 || path[3] != 4 {
+		t.Fatalf("expected 1..4 in 3 hops, got %v", path)
+	}
+}
+
+func TestBidirectionalPath_DepthLimit(t *testing.T) {
+	expand := fixtureExpand([][2]int{{1, 2}, {2, 3}, {3, 4}, {4, 5}})
+
+	path, err := bidirectionalPath(1, 5, 3, 1000, expand)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != nil {
+		t.Fatalf("expected no path within 3 hops, got %v", path)
+	}
+
+	if path, _ := bidirectionalPath(1, 5, 4, 1000, expand); len(path) != 5 {
+		t.Fatalf("expected a 4-hop path at depth 4, got %v", path)
+	}
+}
@@ -0,0 +1,77 @@
+package search
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+//
+// ========================================================================
+// Connection pool settings
+// ========================================================================
+//
+
+// PoolConfig sizes the database/sql pool behind a Store.
+type PoolConfig struct {
+	MaxOpen     int
+	MaxIdle     int
+	MaxLifetime time.Duration
+	MaxIdleTime time.Duration
+}
+
+// DefaultPoolConfig suits one API process sharing a Store across handlers
+// and background searches.
+var DefaultPoolConfig = PoolConfig{
+	MaxOpen:     20,
+	MaxIdle:     10,
+	MaxLifetime: 30 * time.Minute,
+	MaxIdleTime: 5 * time.Minute,
+}
+
+// PoolConfigFromEnv reads PG_MAX_OPEN_CONNS, PG_MAX_IDLE_CONNS,
+// PG_CONN_MAX_LIFETIME and PG_CONN_MAX_IDLE_TIME, falling back to
+// DefaultPoolConfig for unset ones. Durations use time.ParseDuration
+// syntax ("30m").
+func PoolConfigFromEnv() (PoolConfig, error) {
+	c := DefaultPoolConfig
+
+	for _, v := range []struct {
+		key string
+		dst *int
+	}{
+		{"PG_MAX_OPEN_CONNS", &c.MaxOpen},
+		{"PG_MAX_IDLE_CONNS", &c.MaxIdle},
+	} {
+		raw := os.Getenv(v.key)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return c, fmt.Errorf("%s: invalid count %q", v.key, raw)
+		}
+		*v.dst = n
+	}
+
+	for _, v := range []struct {
+		key string
+		dst *time.Duration
+	}{
+		{"PG_CONN_MAX_LIFETIME", &c.MaxLifetime},
+		{"PG_CONN_MAX_IDLE_TIME", &c.MaxIdleTime},
+	} {
+		raw := os.Getenv(v.key)
+		if raw == "" {
+			continue
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < 0 {
+			return c, fmt.Errorf("%s: invalid duration %q", v.key, raw)
+		}
+		*v.dst = d
+	}
+
+	return c, nil
+}
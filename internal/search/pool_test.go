@@ -0,0 +1,43 @@
+package search
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPoolConfigFromEnv_Defaults(t *testing.T) {
+	for _, k := range []string{"PG_MAX_OPEN_CONNS", "PG_MAX_IDLE_CONNS", "PG_CONN_MAX_LIFETIME", "PG_CONN_MAX_IDLE_TIME"} {
+		t.Setenv(k, "")
+	}
+
+	c, err := PoolConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c != DefaultPoolConfig {
+		t.Fatalf("expected defaults, got %+v", c)
+	}
+}
+
+func TestPoolConfigFromEnv_Overrides(t *testing.T) {
+	t.Setenv("PG_MAX_OPEN_CONNS", "50")
+	t.Setenv("PG_MAX_IDLE_CONNS", "5")
+	t.Setenv("PG_CONN_MAX_LIFETIME", "1h")
+	t.Setenv("PG_CONN_MAX_IDLE_TIME", "")
+
+	c, err := PoolConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := PoolConfig{MaxOpen: 50, MaxIdle: 5, MaxLifetime: time.Hour, MaxIdleTime: DefaultPoolConfig.MaxIdleTime}
+	if c != want {
+		t.Fatalf("got %+v, want %+v", c, want)
+	}
+}
+
+func TestPoolConfigFromEnv_Invalid(t *testing.T) {
+	t.Setenv("PG_MAX_OPEN_CONNS", "lots")
+	if _, err := PoolConfigFromEnv(); err == nil {
+		t.Fatal("expected an error for a non-numeric pool size")
+	}
+}
@@ -0,0 +1,37 @@
+package search
+
+import (
+	"context"
+)
+
+//
+// ========================================================================
+// MBID redirects
+// ========================================================================
+//
+// When MusicBrainz merges artists, the MBIDs of the merged-away artists
+// move to artist_gid_redirect, pointing at the surviving artist. Cached
+// IDs and shared links keep using the old MBIDs, so every lookup by MBID
+// goes through artistIDByGID and responses carry the canonical gid.
+
+// artistIDByGID returns a scalar subquery yielding the id of the artist
+// whose MBID is in the given placeholder expression, following a redirect
+// when the MBID belongs to a merged artist.
+func artistIDByGID(param string) string {
+	return `(
+		SELECT id FROM artist WHERE gid = ` + param + `
+		UNION ALL
+		SELECT new_id FROM artist_gid_redirect WHERE gid = ` + param + `
+		LIMIT 1
+	)`
+}
+
+// CanonicalMBID returns the current MBID for mbid: mbid itself, or the
+// MBID of the artist it was merged into. Unknown MBIDs yield sql.ErrNoRows.
+func (s *Store) CanonicalMBID(ctx context.Context, mbid string) (string, error) {
+	var out string
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT gid::text FROM artist WHERE id = `+artistIDByGID("$1")+`;
+	`, mbid).Scan(&out)
+	return out, err
+}
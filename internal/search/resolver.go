@@ -0,0 +1,305 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	sixdegrees "github.com/Jonnymurillo288/MelodyMap/sixDegrees"
+	"github.com/agext/levenshtein"
+	"github.com/google/uuid"
+)
+
+//
+// ========================================================================
+// Fuzzy artist resolution
+// ========================================================================
+//
+
+const (
+	// resolveCandidatePool is how many trigram matches are rescored.
+	resolveCandidatePool = 50
+	// ResolveDefaultLimit is how many ranked candidates are returned.
+	ResolveDefaultLimit = 5
+	// ResolveAcceptNameScore is the normalized-name similarity below which
+	// the best candidate is not taken as a match without asking.
+	ResolveAcceptNameScore = 0.6
+
+	// weights of the ranking score; they sum to 1
+	resolveWeightName    = 0.6
+	resolveWeightTrigram = 0.25
+	resolveWeightDegree  = 0.15
+)
+
+// Ways a candidate can match the typed name.
+const (
+	MatchedByName          = "name"
+	MatchedBySortName      = "sort_name"
+	MatchedByAlias         = "alias"
+	MatchedByAliasSortName = "alias_sort_name"
+)
+
+// ArtistCandidate is one possible match for a typed artist name. Name is
+// always the canonical artist name; MatchedName is the name, sort name or
+// alias that actually matched.
+type ArtistCandidate struct {
+	ID           int     `json:"-"`
+	MBID         string  `json:"mbid"`
+	Name         string  `json:"name"`
+	Comment      string  `json:"comment,omitempty"` // MusicBrainz disambiguation
+	MatchedName  string  `json:"matchedName"`
+	MatchedBy    string  `json:"matchedBy"`
+	AliasLocale  string  `json:"aliasLocale,omitempty"`
+	PrimaryAlias bool    `json:"primaryAlias,omitempty"` // primary alias for its locale
+	Degree       int     `json:"degree"`                 // distinct collaborators
+	Trigram      float64 `json:"trigram"`                // pg_trgm similarity of lower-cased names
+	NameScore    float64 `json:"nameScore"`              // Levenshtein similarity of normalized names
+	Score        float64 `json:"score"`
+}
+
+// Resolution is the ranked outcome of resolving a name. Chosen is nil
+// when nothing matched.
+type Resolution struct {
+	Query      string            `json:"query"`
+	Chosen     *ArtistCandidate  `json:"chosen"`
+	Confidence float64           `json:"confidence"`
+	Candidates []ArtistCandidate `json:"candidates"`
+}
+
+// migrateResolver enables pg_trgm and the trigram indexes the resolver
+// needs on names, sort names and aliases.
+func (s *Store) migrateResolver(ctx context.Context) error {
+	q := `
+		CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+		CREATE INDEX IF NOT EXISTS artist_lower_name_trgm_idx
+			ON artist USING gin (lower(name) gin_trgm_ops);
+
+		CREATE INDEX IF NOT EXISTS artist_lower_sort_name_trgm_idx
+			ON artist USING gin (lower(sort_name) gin_trgm_ops);
+
+		CREATE INDEX IF NOT EXISTS artist_alias_lower_name_trgm_idx
+			ON artist_alias USING gin (lower(name) gin_trgm_ops);
+
+		CREATE INDEX IF NOT EXISTS artist_alias_lower_sort_name_trgm_idx
+			ON artist_alias USING gin (lower(sort_name) gin_trgm_ops);
+	`
+	_, err := s.DB.ExecContext(ctx, q)
+	return err
+}
+
+// ResolveArtist finds the artists whose names best match a typed name,
+// tolerating typos, diacritics and a missing or extra "The". The name,
+// sort name and every alias (including locale-specific primary aliases)
+// are searched, and each artist keeps its best-matching one. Trigram
+// similarity selects a candidate pool that is rescored on normalized-name
+// edit distance and collaboration degree, so among homonyms the artist
+// with the larger network wins.
+func (s *Store) ResolveArtist(ctx context.Context, name string, limit int) (*Resolution, error) {
+	if limit <= 0 {
+		limit = ResolveDefaultLimit
+	}
+
+	query := strings.ToLower(strings.TrimSpace(name))
+	res := &Resolution{Query: name, Candidates: []ArtistCandidate{}}
+	if query == "" {
+		return res, nil
+	}
+
+	q := `
+		WITH matches AS (
+			SELECT a.id AS artist_id, a.name AS matched, 'name' AS matched_by,
+				'' AS locale, false AS primary_alias,
+				similarity(lower(a.name), $1) AS sim
+			FROM artist a
+			WHERE lower(a.name) % $1
+
+			UNION ALL
+
+			SELECT a.id, a.sort_name, 'sort_name', '', false,
+				similarity(lower(a.sort_name), $1)
+			FROM artist a
+			WHERE lower(a.sort_name) % $1
+
+			UNION ALL
+
+			SELECT al.artist, al.name, 'alias', COALESCE(al.locale, ''), al.primary_for_locale,
+				similarity(lower(al.name), $1)
+			FROM artist_alias al
+			WHERE lower(al.name) % $1
+
+			UNION ALL
+
+			SELECT al.artist, al.sort_name, 'alias_sort_name', COALESCE(al.locale, ''), al.primary_for_locale,
+				similarity(lower(al.sort_name), $1)
+			FROM artist_alias al
+			WHERE lower(al.sort_name) % $1
+		),
+		best AS (
+			-- one row per artist: its best match, preferring the real name,
+			-- then primary aliases, on ties
+			SELECT DISTINCT ON (artist_id) *
+			FROM matches
+			ORDER BY artist_id, sim DESC, (matched_by = 'name') DESC, primary_alias DESC
+		),
+		pool AS (
+			SELECT * FROM best
+			ORDER BY sim DESC, artist_id
+			LIMIT $2
+		)
+		SELECT
+			a.id,
+			a.gid::text,
+			a.name,
+			a.comment,
+			p.matched,
+			p.matched_by,
+			p.locale,
+			p.primary_alias,
+			p.sim,
+			(SELECT count(*) FROM artist_collab_summary cs WHERE cs.artist_id = a.id) AS degree
+		FROM pool p
+		JOIN artist a ON a.id = p.artist_id
+		ORDER BY p.sim DESC, a.id;
+	`
+	rows, err := s.DB.QueryContext(ctx, q, query, resolveCandidatePool)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cands []ArtistCandidate
+	for rows.Next() {
+		var c ArtistCandidate
+		if err := rows.Scan(
+			&c.ID, &c.MBID, &c.Name, &c.Comment,
+			&c.MatchedName, &c.MatchedBy, &c.AliasLocale, &c.PrimaryAlias,
+			&c.Trigram, &c.Degree,
+		); err != nil {
+			return nil, err
+		}
+		cands = append(cands, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ranked, confidence := rankCandidates(name, cands)
+	if len(ranked) == 0 {
+		return res, nil
+	}
+
+	chosen := ranked[0]
+	res.Chosen = &chosen
+	res.Confidence = confidence
+	res.Candidates = ranked[:min(len(ranked), limit)]
+	return res, nil
+}
+
+// rankCandidates scores candidates against the typed name and sorts them
+// best first. The confidence of the winner combines how well its name
+// matches with how clearly it beats the runner-up, so an exact but common
+// name ("John Williams") resolves with lower confidence than a unique one.
+func rankCandidates(name string, cands []ArtistCandidate) ([]ArtistCandidate, float64) {
+	if len(cands) == 0 {
+		return nil, 0
+	}
+
+	key := sixdegrees.NormalizeArtistName(name)
+
+	maxDegree := 0
+	for _, c := range cands {
+		maxDegree = max(maxDegree, c.Degree)
+	}
+
+	out := make([]ArtistCandidate, len(cands))
+	for i, c := range cands {
+		matched := c.MatchedName
+		switch {
+		case matched == "":
+			matched = c.Name
+		case c.MatchedBy == MatchedBySortName || c.MatchedBy == MatchedByAliasSortName:
+			matched = unsortName(matched)
+		}
+		c.NameScore = levenshtein.Similarity(key, sixdegrees.NormalizeArtistName(matched), nil)
+
+		degreeScore := 0.0
+		if maxDegree > 0 {
+			degreeScore = math.Log1p(float64(c.Degree)) / math.Log1p(float64(maxDegree))
+		}
+
+		c.Score = resolveWeightName*c.NameScore +
+			resolveWeightTrigram*c.Trigram +
+			resolveWeightDegree*degreeScore
+		out[i] = c
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].ID < out[j].ID
+	})
+
+	margin := 1.0
+	if len(out) > 1 && out[0].Score > 0 {
+		margin = (out[0].Score - out[1].Score) / out[0].Score
+	}
+	confidence := out[0].NameScore * (0.5 + 0.5*margin)
+	return out, confidence
+}
+
+// unsortName turns a MusicBrainz sort name back into reading order:
+// "Beatles, The" -> "The Beatles", "Jackson, Michael" -> "Michael Jackson".
+func unsortName(sortName string) string {
+	last, first, ok := strings.Cut(sortName, ", ")
+	if !ok || strings.Contains(first, ", ") {
+		return sortName
+	}
+	return first + " " + last
+}
+
+// ResolveArtistOnce resolves a typed name to the best-ranked artist from
+// ResolveArtist, rejecting candidates that are too far from it.
+func (s *Store) ResolveArtistOnce(ctx context.Context, name string) (*sixdegrees.Artists, error) {
+	res, err := s.ResolveArtist(ctx, name, 1)
+	if err != nil {
+		return nil, fmt.Errorf("resolve artist: %w", err)
+	}
+	if res.Chosen == nil || res.Chosen.NameScore < ResolveAcceptNameScore {
+		return nil, fmt.Errorf("artist not found: %w", sql.ErrNoRows)
+	}
+
+	return &sixdegrees.Artists{
+		ID:   res.Chosen.MBID, // UUID (gid)
+		Name: res.Chosen.Name,
+	}, nil
+}
+
+// ResolveArtistMBID looks an artist up by MBID, without any name matching.
+// MBIDs of merged artists resolve to the artist they were merged into.
+func (s *Store) ResolveArtistMBID(ctx context.Context, mbid string) (*sixdegrees.Artists, error) {
+	a := &sixdegrees.Artists{}
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT gid::text, name FROM artist WHERE id = `+artistIDByGID("$1")+`;
+	`, mbid).Scan(&a.ID, &a.Name)
+	if err != nil {
+		return nil, fmt.Errorf("artist %s: %w", mbid, err)
+	}
+	return a, nil
+}
+
+// resolveEndpoint resolves one end of a search, given an MBID, a Spotify
+// artist URI or URL, or a name.
+func (s *Store) resolveEndpoint(ctx context.Context, nameOrMBID string) (*sixdegrees.Artists, error) {
+	if _, err := uuid.Parse(nameOrMBID); err == nil {
+		return s.ResolveArtistMBID(ctx, nameOrMBID)
+	}
+	if id, ok := ParseSpotifyArtistID(nameOrMBID); ok {
+		return s.ResolveSpotifyArtist(ctx, id)
+	}
+	return s.ResolveArtistOnce(ctx, nameOrMBID)
+}
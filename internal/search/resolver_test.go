@@ -0,0 +1,81 @@
+package search
+
+import "testing"
+
+func TestRankCandidates_NormalizesNames(t *testing.T) {
+	cands := []ArtistCandidate{
+		{ID: 1, Name: "Beatles Revival Band", Trigram: 0.5, Degree: 3},
+		{ID: 2, Name: "The Beatles", Trigram: 0.6, Degree: 400},
+	}
+
+	ranked, conf := rankCandidates("beatles", cands)
+	if ranked[0].ID != 2 {
+		t.Fatalf("expected The Beatles first, got %+v", ranked[0])
+	}
+	if ranked[0].NameScore != 1 {
+		t.Fatalf("expected an exact normalized match, got %v", ranked[0].NameScore)
+	}
+	if conf <= 0.5 || conf > 1 {
+		t.Fatalf("unexpected confidence %v", conf)
+	}
+}
+
+func TestRankCandidates_HomonymsPreferLargerNetwork(t *testing.T) {
+	cands := []ArtistCandidate{
+		{ID: 10, Name: "John Williams", Comment: "British guitarist", Trigram: 1, Degree: 20},
+		{ID: 11, Name: "John Williams", Comment: "US composer", Trigram: 1, Degree: 900},
+	}
+
+	ranked, conf := rankCandidates("john williams", cands)
+	if ranked[0].ID != 11 {
+		t.Fatalf("expected the better-connected homonym first, got %+v", ranked[0])
+	}
+
+	unique, uniqueConf := rankCandidates("john williams", cands[1:])
+	if unique[0].ID != 11 || uniqueConf <= conf {
+		t.Fatalf("a unique exact match should be more confident: %v vs %v", uniqueConf, conf)
+	}
+}
+
+func TestRankCandidates_Empty(t *testing.T) {
+	ranked, conf := rankCandidates("nobody", nil)
+	if ranked != nil || conf != 0 {
+		t.Fatalf("expected no result, got %v %v", ranked, conf)
+	}
+}
+
+func TestRankCandidates_AliasAndSortName(t *testing.T) {
+	cands := []ArtistCandidate{
+		{ID: 1, Name: "Diddy Kong Racing Band", MatchedName: "Diddy Kong Racing Band", MatchedBy: MatchedByName, Trigram: 0.4},
+		{ID: 2, Name: "Diddy", MatchedName: "P. Diddy", MatchedBy: MatchedByAlias, Trigram: 0.8, Degree: 500},
+	}
+	ranked, _ := rankCandidates("P Diddy", cands)
+	if ranked[0].ID != 2 || ranked[0].MatchedBy != MatchedByAlias {
+		t.Fatalf("expected the alias match first, got %+v", ranked[0])
+	}
+	if ranked[0].NameScore != 1 {
+		t.Fatalf("alias should be compared, not the canonical name: %v", ranked[0].NameScore)
+	}
+
+	sorted := []ArtistCandidate{
+		{ID: 3, Name: "Michael Jackson", MatchedName: "Jackson, Michael", MatchedBy: MatchedBySortName, Trigram: 0.5},
+	}
+	ranked, _ = rankCandidates("michael jackson", sorted)
+	if ranked[0].NameScore != 1 {
+		t.Fatalf("sort name should be read in natural order, got %v", ranked[0].NameScore)
+	}
+}
+
+func TestUnsortName(t *testing.T) {
+	cases := map[string]string{
+		"Beatles, The":     "The Beatles",
+		"Jackson, Michael": "Michael Jackson",
+		"Adele":            "Adele",
+		"a, b, c":          "a, b, c",
+	}
+	for in, want := range cases {
+		if got := unsortName(in); got != want {
+			t.Fatalf("unsortName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
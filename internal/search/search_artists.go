@@ -1,18 +1,21 @@
 package search
 
 import (
+	"context"
 	"fmt"
-	"os"
 	"strconv"
 	"time"
 )
 
-// This version matches your handlers & background BFS.
-// No provider or MB client required from caller.
-func SearchArtists(
+// bfsTracksPerHop is how much evidence is loaded for each hop of a found
+// shortest path.
+const bfsTracksPerHop = 50
+
+// SearchArtists runs the shortest-path search between two artists, each
+// given by name, MBID or Spotify artist URI.
+func (e *Engine) SearchArtists(
 	start, target string,
 	depth, limit int,
-	offline bool,
 ) (int,
 	[]Step,
 	string,
@@ -24,11 +27,12 @@ func SearchArtists(
 		return 0, nil, "start or target empty", 400, nil
 	}
 
+	ctx := context.Background()
 	startTime := time.Now().UTC().Unix()
 
 	// ------------------------
 	// Resolve START
-	startArtist, err := ResolveArtistOnce(os.Getenv("PG_DSN"), start)
+	startArtist, err := e.Graph.Resolve(ctx, start)
 	if err != nil {
 		return 0, nil, "start artist not found", 404, nil
 	}
@@ -36,25 +40,28 @@ func SearchArtists(
 	// ------------------------
 	// Resolve TARGET
 
-	targetArtist, err := ResolveArtistOnce(os.Getenv("PG_DSN"), target)
+	targetArtist, err := e.Graph.Resolve(ctx, target)
 	if err != nil {
 		return 0, nil, "target artist not found", 404, nil
 	}
 
 	// ------------------------
 	// BFS call
-	helper, _, pathIDs, tracksPerHop, status, ok := RunSearchOptsBFS(
+	helper, _, pathIDs, status, ok := e.RunSearchOptsBFS(
+		ctx,
 		startArtist,
 		targetArtist,
 		depth,
 		true, // verbose
 		&limit,
-		offline,
 	)
 
 	if status == 429 {
 		return 0, nil, "", 429, fmt.Errorf("rate limit")
 	}
+	if status == 502 {
+		return 0, nil, "graph source failed", 502, fmt.Errorf("graph source failed")
+	}
 	if !ok || len(pathIDs) == 0 {
 		msg := fmt.Sprintf("no path found between %q and %q", start, target)
 		if depth >= 0 {
@@ -64,31 +71,10 @@ func SearchArtists(
 	}
 
 	// ------------------------
-	// Build []Step
-	var steps []Step
-
-	for i := 1; i < len(pathIDs); i++ {
-		from := helper.ArtistByID[pathIDs[i-1]]
-		to := helper.ArtistByID[pathIDs[i]]
-
-		step := Step{
-			From: from.Name,
-			To:   to.Name,
-		}
-
-		if i-1 < len(tracksPerHop) {
-			for _, t := range tracksPerHop[i-1] {
-				step.Tracks = append(step.Tracks, TrackInfo{
-					ID:            t.ID,
-					Name:          t.Name,
-					RecordingID:   t.RecordingID,
-					RecordingName: t.RecordingName,
-					PhotoURL:      t.PhotoURL,
-				})
-			}
-		}
-
-		steps = append(steps, step)
+	// Build []Step, loading evidence only for the hops on the path
+	steps, err := stepsForPath(ctx, e.Graph, pathRefs(helper, pathIDs), bfsTracksPerHop)
+	if err != nil {
+		return 0, nil, "failed to load hop evidence", 500, err
 	}
 
 	endTime := time.Now().UTC().Unix()
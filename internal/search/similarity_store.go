@@ -0,0 +1,259 @@
+package search
+
+import (
+	"context"
+	"math"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/analytics"
+)
+
+//
+// ========================================================================
+// Similar artists (LSH index written by cmd/similarity)
+// ========================================================================
+//
+
+// similarCandidateLimit caps how many LSH candidates are rescored exactly,
+// preferring those that share the most bands with the source.
+const similarCandidateLimit = 2000
+
+// SimilarArtist is one artist whose collaborators overlap the source's.
+type SimilarArtist struct {
+	ArtistRef
+	Cosine  float64 `json:"cosine"`
+	Jaccard float64 `json:"jaccard"`
+	Overlap int     `json:"overlap"`
+}
+
+// SaveLSHBuckets replaces the similarity index in one transaction. fill
+// streams rows through emit, which batches them into unnest() inserts so
+// the full index never has to sit in memory. Buckets holding more than
+// maxBucket artists (typically the one-off guests of a single hub) carry
+// no signal and are dropped.
+func (s *Store) SaveLSHBuckets(
+	ctx context.Context,
+	maxBucket int,
+	fill func(emit func(band int16, bucket int64, artistID int32) error) error,
+) error {
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `TRUNCATE artist_lsh_bucket;`); err != nil {
+		return err
+	}
+
+	var bands []int16
+	var buckets []int64
+	var artists []int32
+
+	flush := func() error {
+		if len(artists) == 0 {
+			return nil
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO artist_lsh_bucket (band, bucket, artist_id)
+			SELECT * FROM unnest($1::smallint[], $2::bigint[], $3::int[])
+			ON CONFLICT DO NOTHING;
+		`, bands, buckets, artists)
+		bands, buckets, artists = bands[:0], buckets[:0], artists[:0]
+		return err
+	}
+
+	err = fill(func(band int16, bucket int64, artistID int32) error {
+		bands = append(bands, band)
+		buckets = append(buckets, bucket)
+		artists = append(artists, artistID)
+		if len(artists) >= analyticsWriteBatch {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	prune := `
+		DELETE FROM artist_lsh_bucket b
+		USING (
+			SELECT band, bucket
+			FROM artist_lsh_bucket
+			GROUP BY band, bucket
+			HAVING count(*) > $1
+		) big
+		WHERE b.band = big.band AND b.bucket = big.bucket;
+	`
+	if _, err := tx.ExecContext(ctx, prune, maxBucket); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SimilarArtists finds artists that share an LSH bucket with the source,
+// then rescores them exactly by shared-recording weighted cosine over
+// artist_collab_summary. Returns sql.ErrNoRows for an unknown MBID and an
+// empty list when the artist is not in the index.
+func (s *Store) SimilarArtists(ctx context.Context, mbid string, limit int) ([]SimilarArtist, error) {
+	a, err := s.LookupArtistByMBID(mbid)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.lshCandidates(ctx, a.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return []SimilarArtist{}, nil
+	}
+
+	source, err := s.collabWeights(ctx, a.ID)
+	if err != nil {
+		return nil, err
+	}
+	sourceIDs := make([]int, 0, len(source))
+	for n := range source {
+		sourceIDs = append(sourceIDs, n)
+	}
+
+	overlaps := make(map[int]map[int]int, len(candidates))
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT artist_id, neighbor_artist_id, shared_recordings
+		FROM artist_collab_summary
+		WHERE artist_id = ANY($1)
+		  AND neighbor_artist_id = ANY($2);
+	`, candidates, sourceIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c, n, shared int
+		if err := rows.Scan(&c, &n, &shared); err != nil {
+			return nil, err
+		}
+		if overlaps[c] == nil {
+			overlaps[c] = make(map[int]int)
+		}
+		overlaps[c][n] = shared
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	degree, norm, err := s.collabVectorStats(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	ranked := analytics.RankSimilar(source, overlaps, degree, norm, limit)
+
+	ids := make([]int, len(ranked))
+	for i, r := range ranked {
+		ids[i] = r.ID
+	}
+	refs, err := s.ArtistRefsByID(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]SimilarArtist, 0, len(ranked))
+	for _, r := range ranked {
+		out = append(out, SimilarArtist{
+			ArtistRef: refs[r.ID],
+			Cosine:    r.Cosine,
+			Jaccard:   r.Jaccard,
+			Overlap:   r.Overlap,
+		})
+	}
+	return out, nil
+}
+
+// lshCandidates returns artists sharing at least one band bucket with id,
+// most shared bands first.
+func (s *Store) lshCandidates(ctx context.Context, id int) ([]int, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT b2.artist_id
+		FROM artist_lsh_bucket b1
+		JOIN artist_lsh_bucket b2
+		  ON b2.band = b1.band AND b2.bucket = b1.bucket
+		WHERE b1.artist_id = $1
+		  AND b2.artist_id <> $1
+		GROUP BY b2.artist_id
+		ORDER BY count(*) DESC, b2.artist_id
+		LIMIT $2;
+	`, id, similarCandidateLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []int
+	for rows.Next() {
+		var c int
+		if err := rows.Scan(&c); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// collabWeights returns an artist's full neighbor vector.
+func (s *Store) collabWeights(ctx context.Context, id int) (map[int]int, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT neighbor_artist_id, shared_recordings
+		FROM artist_collab_summary
+		WHERE artist_id = $1;
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[int]int)
+	for rows.Next() {
+		var n, shared int
+		if err := rows.Scan(&n, &shared); err != nil {
+			return nil, err
+		}
+		out[n] = shared
+	}
+	return out, rows.Err()
+}
+
+// collabVectorStats returns each artist's neighbor count and the L2 norm
+// of its shared-recording weights.
+func (s *Store) collabVectorStats(ctx context.Context, ids []int) (map[int]int, map[int]float64, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT artist_id, count(*), sum(shared_recordings::float8 * shared_recordings)
+		FROM artist_collab_summary
+		WHERE artist_id = ANY($1)
+		GROUP BY artist_id;
+	`, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	degree := make(map[int]int, len(ids))
+	norm := make(map[int]float64, len(ids))
+	for rows.Next() {
+		var id, d int
+		var sq float64
+		if err := rows.Scan(&id, &d, &sq); err != nil {
+			return nil, nil, err
+		}
+		degree[id] = d
+		norm[id] = math.Sqrt(sq)
+	}
+	return degree, norm, rows.Err()
+}
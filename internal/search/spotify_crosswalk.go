@@ -0,0 +1,166 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	sixdegrees "github.com/Jonnymurillo288/MelodyMap/sixDegrees"
+)
+
+//
+// ========================================================================
+// Spotify crosswalk
+// ========================================================================
+//
+// MusicBrainz links artists, releases and recordings to their Spotify
+// pages through url and the l_<entity>_url tables. spotify_crosswalk
+// flattens those links into (kind, mbid, spotify_id) rows so either side
+// can be looked up by index instead of searching Spotify by title.
+
+// Entity kinds stored in spotify_crosswalk, named after the MusicBrainz
+// entity. Spotify calls them artist, album and track.
+const (
+	CrosswalkArtist    = "artist"
+	CrosswalkRelease   = "release"
+	CrosswalkRecording = "recording"
+)
+
+// spotifyURLPattern captures the Spotify type and ID of an
+// open.spotify.com URL, with or without a locale prefix.
+const spotifyURLPattern = `^https?://open\.spotify\.com/(?:intl-[a-z_-]+/)?(artist|album|track)/([A-Za-z0-9]{22})`
+
+var (
+	spotifyArtistURI = regexp.MustCompile(`^spotify:artist:([A-Za-z0-9]{22})$`)
+	spotifyArtistURL = regexp.MustCompile(`^https?://open\.spotify\.com/(?:intl-[a-z_-]+/)?artist/([A-Za-z0-9]{22})`)
+)
+
+// RebuildSpotifyCrosswalk recreates spotify_crosswalk from the current
+// URL relationships and returns the number of rows written.
+func (s *Store) RebuildSpotifyCrosswalk(ctx context.Context) (int64, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS spotify_crosswalk (
+			kind TEXT NOT NULL,
+			mbid UUID NOT NULL,
+			spotify_id TEXT NOT NULL,
+			PRIMARY KEY (kind, mbid, spotify_id)
+		);
+
+		CREATE INDEX IF NOT EXISTS spotify_crosswalk_spotify_idx
+			ON spotify_crosswalk (kind, spotify_id);
+
+		TRUNCATE spotify_crosswalk;
+	`)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, src := range []struct{ kind, table, link, spotifyType string }{
+		{CrosswalkArtist, "artist", "l_artist_url", "artist"},
+		{CrosswalkRelease, "release", "l_release_url", "album"},
+		{CrosswalkRecording, "recording", "l_recording_url", "track"},
+	} {
+		q := fmt.Sprintf(`
+			INSERT INTO spotify_crosswalk (kind, mbid, spotify_id)
+			SELECT $1, e.gid, m.parts[2]
+			FROM %[2]s l
+			JOIN url u ON u.id = l.entity1
+			JOIN %[1]s e ON e.id = l.entity0
+			CROSS JOIN LATERAL regexp_match(u.url, $2) AS m(parts)
+			WHERE u.url LIKE '%%open.spotify.com/%%'
+			  AND m.parts[1] = $3
+			ON CONFLICT DO NOTHING;
+		`, src.table, src.link)
+
+		res, err := tx.ExecContext(ctx, q, src.kind, spotifyURLPattern, src.spotifyType)
+		if err != nil {
+			return 0, fmt.Errorf("crosswalk %s: %w", src.kind, err)
+		}
+		n, _ := res.RowsAffected()
+		total += n
+	}
+
+	return total, tx.Commit()
+}
+
+// SpotifyIDsForMBIDs maps MBIDs of one kind to a linked Spotify ID. MBIDs
+// without a link are absent from the result.
+func (s *Store) SpotifyIDsForMBIDs(ctx context.Context, kind string, mbids []string) (map[string]string, error) {
+	return s.crosswalk(ctx, `
+		SELECT DISTINCT ON (mbid) mbid::text, spotify_id
+		FROM spotify_crosswalk
+		WHERE kind = $1 AND mbid = ANY($2::uuid[])
+		ORDER BY mbid, spotify_id;
+	`, kind, mbids)
+}
+
+// MBIDsForSpotifyIDs maps Spotify IDs of one kind back to MBIDs.
+func (s *Store) MBIDsForSpotifyIDs(ctx context.Context, kind string, spotifyIDs []string) (map[string]string, error) {
+	return s.crosswalk(ctx, `
+		SELECT DISTINCT ON (spotify_id) spotify_id, mbid::text
+		FROM spotify_crosswalk
+		WHERE kind = $1 AND spotify_id = ANY($2)
+		ORDER BY spotify_id, mbid;
+	`, kind, spotifyIDs)
+}
+
+func (s *Store) crosswalk(ctx context.Context, q, kind string, keys []string) (map[string]string, error) {
+	out := make(map[string]string, len(keys))
+	if len(keys) == 0 {
+		return out, nil
+	}
+
+	rows, err := s.DB.QueryContext(ctx, q, kind, keys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var k, v string
+		if err := rows.Scan(&k, &v); err != nil {
+			return nil, err
+		}
+		out[k] = v
+	}
+	return out, rows.Err()
+}
+
+// ParseSpotifyArtistID extracts the artist ID from a spotify:artist: URI
+// or an open.spotify.com artist URL.
+func ParseSpotifyArtistID(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if m := spotifyArtistURI.FindStringSubmatch(s); m != nil {
+		return m[1], true
+	}
+	if m := spotifyArtistURL.FindStringSubmatch(s); m != nil {
+		return m[1], true
+	}
+	return "", false
+}
+
+// ResolveSpotifyArtist maps a Spotify artist ID to its MusicBrainz artist
+// through the crosswalk.
+func (s *Store) ResolveSpotifyArtist(ctx context.Context, spotifyID string) (*sixdegrees.Artists, error) {
+	a := &sixdegrees.Artists{}
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT a.gid::text, a.name
+		FROM spotify_crosswalk x
+		JOIN artist a ON a.gid = x.mbid
+		WHERE x.kind = 'artist' AND x.spotify_id = $1
+		ORDER BY a.id
+		LIMIT 1;
+	`, spotifyID).Scan(&a.ID, &a.Name)
+	if err != nil {
+		return nil, fmt.Errorf("spotify artist %s: %w", spotifyID, err)
+	}
+	return a, nil
+}
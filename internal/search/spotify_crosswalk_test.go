@@ -0,0 +1,22 @@
+package search
+
+import "testing"
+
+func TestParseSpotifyArtistID(t *testing.T) {
+	const id = "3WrFJ7ztbogyGnTHbHJFl2"
+	cases := map[string]bool{
+		"spotify:artist:" + id:                            true,
+		"https://open.spotify.com/artist/" + id:           true,
+		"https://open.spotify.com/artist/" + id + "?si=x": true,
+		"https://open.spotify.com/intl-de/artist/" + id:   true,
+		"https://open.spotify.com/track/" + id:            false,
+		"spotify:artist:short":                            false,
+		"The Beatles":                                     false,
+	}
+	for in, want := range cases {
+		got, ok := ParseSpotifyArtistID(in)
+		if ok != want || (ok && got != id) {
+			t.Fatalf("ParseSpotifyArtistID(%q) = %q, %v", in, got, ok)
+		}
+	}
+}
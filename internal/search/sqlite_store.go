@@ -0,0 +1,344 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	sixdegrees "github.com/Jonnymurillo288/MelodyMap/sixDegrees"
+	_ "modernc.org/sqlite"
+)
+
+//
+// ========================================================================
+// SQLite store
+// ========================================================================
+//
+// A single-file database holding a trimmed copy of the collaboration
+// graph: artists, the neighbor summary and a few tracks of evidence per
+// edge. It is built by cmd/sqlite from Postgres and lets a laptop run
+// real searches without a MusicBrainz mirror (GRAPH_SOURCE=sqlite).
+
+// SQLiteStore is a GraphSource over a SQLite file written by ImportGraph.
+type SQLiteStore struct {
+	DB *sql.DB
+}
+
+const sqliteSchema = `
+	CREATE TABLE IF NOT EXISTS artist (
+		id       INTEGER PRIMARY KEY,
+		gid      TEXT NOT NULL UNIQUE,
+		name     TEXT NOT NULL,
+		name_key TEXT NOT NULL -- sixdegrees.NormalizeArtistName(name)
+	);
+	CREATE INDEX IF NOT EXISTS artist_name_key_idx ON artist (name_key);
+
+	CREATE TABLE IF NOT EXISTS artist_collab_summary (
+		artist_id          INTEGER NOT NULL,
+		neighbor_artist_id INTEGER NOT NULL,
+		shared_recordings  INTEGER NOT NULL,
+		PRIMARY KEY (artist_id, neighbor_artist_id)
+	) WITHOUT ROWID;
+
+	-- evidence is stored once per pair, lower artist id first
+	CREATE TABLE IF NOT EXISTS edge_track (
+		artist_id          INTEGER NOT NULL,
+		neighbor_artist_id INTEGER NOT NULL,
+		position           INTEGER NOT NULL,
+		track_gid          TEXT NOT NULL,
+		track_name         TEXT NOT NULL,
+		recording_gid      TEXT NOT NULL,
+		recording_name     TEXT NOT NULL,
+		photo_url          TEXT NOT NULL,
+		release_title      TEXT NOT NULL,
+		release_group_gid  TEXT NOT NULL,
+		release_year       INTEGER NOT NULL,
+		release_type       TEXT NOT NULL,
+		credits            TEXT NOT NULL, -- JSON []CreditInfo
+		PRIMARY KEY (artist_id, neighbor_artist_id, position)
+	) WITHOUT ROWID;
+`
+
+// OpenSQLite opens or creates a SQLite graph file.
+func OpenSQLite(path string) (*SQLiteStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("sqlite path is empty")
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sqlite schema: %w", err)
+	}
+	return &SQLiteStore{DB: db}, nil
+}
+
+func (s *SQLiteStore) Close() error {
+	if s == nil || s.DB == nil {
+		return nil
+	}
+	return s.DB.Close()
+}
+
+// ImportGraph replaces the file's contents with g. Artist ids follow the
+// order artists were added to g.
+func (s *SQLiteStore) ImportGraph(ctx context.Context, g *MemoryGraph) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, t := range []string{"edge_track", "artist_collab_summary", "artist"} {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+t); err != nil {
+			return err
+		}
+	}
+
+	insArtist, err := tx.PrepareContext(ctx, `
+		INSERT INTO artist (id, gid, name, name_key) VALUES (?, ?, ?, ?);
+	`)
+	if err != nil {
+		return err
+	}
+	defer insArtist.Close()
+
+	insSummary, err := tx.PrepareContext(ctx, `
+		INSERT INTO artist_collab_summary (artist_id, neighbor_artist_id, shared_recordings)
+		VALUES (?, ?, ?);
+	`)
+	if err != nil {
+		return err
+	}
+	defer insSummary.Close()
+
+	insTrack, err := tx.PrepareContext(ctx, `
+		INSERT INTO edge_track (
+			artist_id, neighbor_artist_id, position,
+			track_gid, track_name, recording_gid, recording_name, photo_url,
+			release_title, release_group_gid, release_year, release_type, credits
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`)
+	if err != nil {
+		return err
+	}
+	defer insTrack.Close()
+
+	for i, a := range g.artists {
+		key := sixdegrees.NormalizeArtistName(a.Name)
+		if _, err := insArtist.ExecContext(ctx, i+1, a.MBID, a.Name, key); err != nil {
+			return fmt.Errorf("artist %s: %w", a.MBID, err)
+		}
+	}
+
+	err = g.StreamCollabEdges(ctx, func(a, nb, shared int) error {
+		if _, err := insSummary.ExecContext(ctx, a, nb, shared); err != nil {
+			return err
+		}
+		if a > nb {
+			return nil
+		}
+		for pos, t := range g.adj[a-1][nb-1].tracks {
+			credits, err := json.Marshal(t.Credits)
+			if err != nil {
+				return err
+			}
+			if _, err := insTrack.ExecContext(ctx,
+				a, nb, pos,
+				t.ID, t.Name, t.RecordingID, t.RecordingName, t.PhotoURL,
+				t.ReleaseTitle, t.ReleaseGroupID, t.ReleaseYear, t.ReleaseType, string(credits),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `ANALYZE;`); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// LookupArtistByMBID finds an artist by MBID.
+func (s *SQLiteStore) LookupArtistByMBID(ctx context.Context, mbid string) (*ArtistInternal, error) {
+	var a ArtistInternal
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT id, gid, name FROM artist WHERE gid = ?;
+	`, strings.ToLower(mbid)).Scan(&a.ID, &a.MBID, &a.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Resolve implements GraphSource by MBID, then by normalized name, then by
+// normalized-name prefix. Ties go to the artist with more collaborators.
+func (s *SQLiteStore) Resolve(ctx context.Context, query string) (*sixdegrees.Artists, error) {
+	if a, err := s.LookupArtistByMBID(ctx, query); err == nil {
+		return &sixdegrees.Artists{ID: a.MBID, Name: a.Name}, nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	key := sixdegrees.NormalizeArtistName(query)
+	if key == "" {
+		return nil, fmt.Errorf("artist %q: %w", query, sql.ErrNoRows)
+	}
+
+	a := &sixdegrees.Artists{}
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT a.gid, a.name
+		FROM artist a
+		WHERE a.name_key = ?1 OR a.name_key LIKE ?2 ESCAPE '\'
+		ORDER BY
+			a.name_key <> ?1,
+			(SELECT count(*) FROM artist_collab_summary cs WHERE cs.artist_id = a.id) DESC,
+			a.id
+		LIMIT 1;
+	`, key, escapeLike(key)+"%").Scan(&a.ID, &a.Name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("artist %q: %w", query, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Neighbors implements GraphSource.
+func (s *SQLiteStore) Neighbors(ctx context.Context, mbid string, limit int) ([]Neighbor, error) {
+	nbs, err := s.NeighborsBatch(ctx, []string{mbid}, limit)
+	if err != nil {
+		return nil, err
+	}
+	return nbs[mbid], nil
+}
+
+// NeighborsBatch implements GraphSource with one query for all artists.
+func (s *SQLiteStore) NeighborsBatch(ctx context.Context, mbids []string, limit int) (map[string][]Neighbor, error) {
+	out := make(map[string][]Neighbor, len(mbids))
+	if len(mbids) == 0 {
+		return out, nil
+	}
+	if limit <= 0 {
+		limit = 200
+	}
+
+	args := make([]any, 0, len(mbids)+1)
+	for _, id := range mbids {
+		args = append(args, strings.ToLower(id))
+	}
+	args = append(args, limit)
+	marks := strings.TrimSuffix(strings.Repeat("?,", len(mbids)), ",")
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT a1.gid, a2.gid, a2.name, ranked.shared_recordings
+		FROM (
+			SELECT
+				cs.artist_id,
+				cs.neighbor_artist_id,
+				cs.shared_recordings,
+				row_number() OVER (
+					PARTITION BY cs.artist_id
+					ORDER BY cs.shared_recordings DESC, cs.neighbor_artist_id
+				) AS rn
+			FROM artist_collab_summary cs
+			JOIN artist a ON a.id = cs.artist_id
+			WHERE a.gid IN (`+marks+`)
+		) ranked
+		JOIN artist a1 ON a1.id = ranked.artist_id
+		JOIN artist a2 ON a2.id = ranked.neighbor_artist_id
+		WHERE ranked.rn <= ?
+		ORDER BY ranked.artist_id, ranked.rn;
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var from string
+		var nb Neighbor
+		if err := rows.Scan(&from, &nb.MBID, &nb.Name, &nb.Shared); err != nil {
+			return nil, err
+		}
+		out[from] = append(out[from], nb)
+	}
+	return out, rows.Err()
+}
+
+// EdgeEvidence implements GraphSource.
+func (s *SQLiteStore) EdgeEvidence(ctx context.Context, from, to ArtistRef, limit int) ([]TrackInfo, error) {
+	if limit <= 0 {
+		limit = 200
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `
+		WITH pair AS (
+			SELECT min(a.id) AS lo, max(a.id) AS hi, count(*) AS n
+			FROM artist a
+			WHERE a.gid IN (?1, ?2)
+		)
+		SELECT
+			t.track_gid, t.track_name, t.recording_gid, t.recording_name, t.photo_url,
+			t.release_title, t.release_group_gid, t.release_year, t.release_type, t.credits
+		FROM edge_track t
+		JOIN pair p ON p.n = 2 AND t.artist_id = p.lo AND t.neighbor_artist_id = p.hi
+		ORDER BY t.position
+		LIMIT ?3;
+	`, strings.ToLower(from.MBID), strings.ToLower(to.MBID), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []TrackInfo
+	for rows.Next() {
+		var t TrackInfo
+		var credits string
+		if err := rows.Scan(
+			&t.ID, &t.Name, &t.RecordingID, &t.RecordingName, &t.PhotoURL,
+			&t.ReleaseTitle, &t.ReleaseGroupID, &t.ReleaseYear, &t.ReleaseType, &credits,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(credits), &t.Credits); err != nil {
+			return nil, fmt.Errorf("credits of %s: %w", t.ID, err)
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// StreamCollabEdges implements EdgeStreamer.
+func (s *SQLiteStore) StreamCollabEdges(ctx context.Context, fn func(artistID, neighborID, shared int) error) error {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT artist_id, neighbor_artist_id, shared_recordings
+		FROM artist_collab_summary
+		ORDER BY artist_id, neighbor_artist_id;
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a, nb, shared int
+		if err := rows.Scan(&a, &nb, &shared); err != nil {
+			return err
+		}
+		if err := fn(a, nb, shared); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
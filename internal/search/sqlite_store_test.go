@@ -0,0 +1,85 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func openFixtureSQLite(t *testing.T) *SQLiteStore {
+	t.Helper()
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "graph.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.ImportGraph(context.Background(), FixtureGraph()); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestSQLiteStore_MatchesMemoryGraph(t *testing.T) {
+	db := openFixtureSQLite(t)
+	mem := FixtureGraph()
+	ctx := context.Background()
+
+	for _, name := range []string{"Alpha", "the charlie", "Fox"} {
+		a, err := db.Resolve(ctx, name)
+		if err != nil {
+			t.Fatalf("resolve %q: %v", name, err)
+		}
+		want, _ := mem.Resolve(ctx, a.Name)
+		if a.ID != want.ID {
+			t.Fatalf("resolve %q: got %s, want %s", name, a.ID, want.ID)
+		}
+
+		got, err := db.Neighbors(ctx, a.ID, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp, _ := mem.Neighbors(ctx, a.ID, 0)
+		if fmt.Sprint(got) != fmt.Sprint(exp) {
+			t.Fatalf("neighbors of %s: got %v, want %v", a.Name, got, exp)
+		}
+	}
+
+	if _, err := db.Resolve(ctx, "Zulu"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestSQLiteStore_EdgeEvidenceBothDirections(t *testing.T) {
+	db := openFixtureSQLite(t)
+	ctx := context.Background()
+
+	charlie, _ := db.Resolve(ctx, "Charlie")
+	bravo, _ := db.Resolve(ctx, "Bravo")
+	c := ArtistRef{MBID: charlie.ID, Name: charlie.Name}
+	b := ArtistRef{MBID: bravo.ID, Name: bravo.Name}
+
+	for _, pair := range [][2]ArtistRef{{b, c}, {c, b}} {
+		tracks, err := db.EdgeEvidence(ctx, pair[0], pair[1], 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tracks) != 1 || tracks[0].Name != "Bravo feat. Charlie" {
+			t.Fatalf("%s -> %s: unexpected evidence %+v", pair[0].Name, pair[1].Name, tracks)
+		}
+	}
+}
+
+func TestEngine_SearchesSQLite(t *testing.T) {
+	e := NewEngine(openFixtureSQLite(t))
+
+	hops, steps, msg, status, err := e.SearchArtists("Alpha", "Echo", 5, 100)
+	if err != nil || status != 200 {
+		t.Fatalf("search failed: %d %q %v", status, msg, err)
+	}
+	if hops != 4 || steps[len(steps)-1].To != "Echo" {
+		t.Fatalf("unexpected path: %d hops, %+v", hops, steps)
+	}
+}
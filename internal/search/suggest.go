@@ -0,0 +1,151 @@
+package search
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+//
+// ========================================================================
+// Artist autocomplete
+// ========================================================================
+//
+
+const (
+	// SuggestMinQuery is the shortest query worth a trigram lookup.
+	SuggestMinQuery = 2
+	// SuggestMaxQuery bounds the query length accepted.
+	SuggestMaxQuery = 100
+	// SuggestDefaultLimit and SuggestMaxLimit bound results per request.
+	SuggestDefaultLimit = 10
+	SuggestMaxLimit     = 25
+
+	suggestPool     = 100 // matches kept for the final ranking
+	suggestCacheTTL = 10 * time.Minute
+)
+
+// ArtistSuggestion is one autocomplete entry.
+type ArtistSuggestion struct {
+	MBID        string `json:"mbid"`
+	Name        string `json:"name"`
+	Comment     string `json:"comment,omitempty"` // MusicBrainz disambiguation
+	Type        string `json:"type,omitempty"`    // Person, Group, ...
+	Country     string `json:"country,omitempty"` // ISO 3166-1 code of the artist's area
+	MatchedName string `json:"matchedName"`       // name or alias that matched
+	Degree      int    `json:"degree"`            // distinct collaborators
+}
+
+type suggestEntry struct {
+	at  time.Time
+	out []ArtistSuggestion
+}
+
+var suggestCache, _ = lru.New(1024)
+
+// SuggestArtists autocompletes a partial artist name over names and
+// aliases. Prefix matches come first, ordered by collaboration degree;
+// fuzzy matches follow, pooled by trigram similarity. Results are cached
+// in-process for a few minutes.
+func (s *Store) SuggestArtists(ctx context.Context, q string, limit int) ([]ArtistSuggestion, error) {
+	q = strings.ToLower(strings.TrimSpace(q))
+	if len(q) < SuggestMinQuery {
+		return []ArtistSuggestion{}, nil
+	}
+	if r := []rune(q); len(r) > SuggestMaxQuery {
+		q = string(r[:SuggestMaxQuery])
+	}
+	if limit <= 0 {
+		limit = SuggestDefaultLimit
+	}
+	limit = min(limit, SuggestMaxLimit)
+
+	key := q + "|" + strconv.Itoa(limit)
+	if v, ok := suggestCache.Get(key); ok {
+		e := v.(suggestEntry)
+		if time.Since(e.at) < suggestCacheTTL {
+			return e.out, nil
+		}
+		suggestCache.Remove(key)
+	}
+
+	sq := `
+		WITH matches AS (
+			SELECT a.id AS artist_id, a.name AS matched,
+				starts_with(lower(a.name), $1) AS prefix,
+				similarity(lower(a.name), $1) AS sim
+			FROM artist a
+			WHERE lower(a.name) LIKE $2 OR lower(a.name) % $1
+
+			UNION ALL
+
+			SELECT al.artist, al.name,
+				starts_with(lower(al.name), $1),
+				similarity(lower(al.name), $1)
+			FROM artist_alias al
+			WHERE lower(al.name) LIKE $2 OR lower(al.name) % $1
+		),
+		best AS (
+			SELECT DISTINCT ON (artist_id) *
+			FROM matches
+			ORDER BY artist_id, prefix DESC, sim DESC
+		),
+		-- prefix matches are pooled by degree, so a short prefix keeps the
+		-- best-connected artists however long their names
+		pool AS (
+			SELECT b.*, COALESCE(d.degree, 0) AS degree
+			FROM best b
+			LEFT JOIN artist_collab_degree d ON d.artist_id = b.artist_id
+			ORDER BY
+				b.prefix DESC,
+				CASE WHEN b.prefix THEN COALESCE(d.degree, 0) END DESC NULLS LAST,
+				b.sim DESC,
+				b.artist_id
+			LIMIT $3
+		)
+		SELECT
+			a.gid::text,
+			a.name,
+			a.comment,
+			COALESCE(t.name, ''),
+			COALESCE(iso.code, ''),
+			p.matched,
+			p.degree
+		FROM pool p
+		JOIN artist a ON a.id = p.artist_id
+		LEFT JOIN artist_type t ON t.id = a.type
+		LEFT JOIN LATERAL (
+			SELECT code FROM iso_3166_1 WHERE area = a.area LIMIT 1
+		) iso ON true
+		ORDER BY p.prefix DESC, p.degree DESC, p.sim DESC, a.id
+		LIMIT $4;
+	`
+	rows, err := s.DB.QueryContext(ctx, sq, q, escapeLike(q)+"%", suggestPool, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []ArtistSuggestion{}
+	for rows.Next() {
+		var a ArtistSuggestion
+		if err := rows.Scan(&a.MBID, &a.Name, &a.Comment, &a.Type, &a.Country, &a.MatchedName, &a.Degree); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	suggestCache.Add(key, suggestEntry{at: time.Now(), out: out})
+	return out, nil
+}
+
+// escapeLike escapes LIKE wildcards so user input only matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,17 @@
+package search
+
+import "testing"
+
+func TestEscapeLike(t *testing.T) {
+	cases := map[string]string{
+		"adele":    "adele",
+		"100%":     `100\%`,
+		"the_band": `the\_band`,
+		`a\b`:      `a\\b`,
+	}
+	for in, want := range cases {
+		if got := escapeLike(in); got != want {
+			t.Fatalf("escapeLike(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -2,18 +2,33 @@ package search
 
 // TrackInfo used in path steps
 type TrackInfo struct {
-	ID            string `json:"id"`
-	Name          string `json:"name"`
-	RecordingID   string `json:"recordingID"`
-	RecordingName string `json:"recordingName"`
-	PhotoURL      string `json:"photoURL"`
+	ID             string       `json:"id"`
+	Name           string       `json:"name"`
+	RecordingID    string       `json:"recordingID"`
+	RecordingName  string       `json:"recordingName"`
+	PhotoURL       string       `json:"photoURL"`
+	ReleaseTitle   string       `json:"releaseTitle,omitempty"`
+	ReleaseGroupID string       `json:"releaseGroupID,omitempty"`
+	ReleaseYear    int          `json:"releaseYear,omitempty"`
+	ReleaseType    string       `json:"releaseType,omitempty"`
+	Credits        []CreditInfo `json:"credits,omitempty"`
+}
+
+// CreditInfo is the "credited as" name an artist used on a track
+type CreditInfo struct {
+	ArtistID   string `json:"artistID"`
+	ArtistName string `json:"artistName"`
+	CreditedAs string `json:"creditedAs"`
 }
 
 // Step in the returned path
 type Step struct {
-	From   string      `json:"from"`
-	To     string      `json:"to"`
-	Tracks []TrackInfo `json:"tracks"`
+	From        string      `json:"from"`
+	To          string      `json:"to"`
+	FromID      string      `json:"fromID,omitempty"`
+	ToID        string      `json:"toID,omitempty"`
+	Tracks      []TrackInfo `json:"tracks"`
+	SharedCount int         `json:"sharedCount,omitempty"` // set by widest-path mode
 }
 
 // SearchResponse returned by background BFS and HTTP layer
@@ -56,9 +71,26 @@ type NeighborEdge struct {
 }
 
 type SearchRequest struct {
-	Start  string `json:"start"`
-	Target string `json:"target"`
-	Depth  int    `json:"depth"`
+	Start      string `json:"start"`
+	Target     string `json:"target"`
+	StartMBID  string `json:"start_mbid,omitempty"`  // skips resolving Start by name
+	TargetMBID string `json:"target_mbid,omitempty"` // skips resolving Target by name
+	Depth      int    `json:"depth"`
+	Mode       string `json:"mode,omitempty"`     // ModeShortest (default), ModeWidest or ModeWeighted
+	Strategy   string `json:"strategy,omitempty"` // ModeWeighted only: StrategyStrength (default) or StrategyPopularity
+}
+
+// Endpoints returns what identifies each end of the search: the MBID
+// when one was given, otherwise the typed name.
+func (r SearchRequest) Endpoints() (start, target string) {
+	start, target = r.Start, r.Target
+	if r.StartMBID != "" {
+		start = r.StartMBID
+	}
+	if r.TargetMBID != "" {
+		target = r.TargetMBID
+	}
+	return start, target
 }
 
 // Minimal local wrappers to avoid sixdegrees import hell
@@ -68,11 +100,16 @@ type ArtistsWrapper struct {
 }
 
 type TrackWrapper struct {
-	ID            string
-	Name          string
-	RecordingID   string
-	RecordingName string
-	PhotoURL      string
+	ID             string
+	Name           string
+	RecordingID    string
+	RecordingName  string
+	PhotoURL       string
+	ReleaseTitle   string
+	ReleaseGroupID string
+	ReleaseYear    int
+	ReleaseType    string
+	Credits        []CreditInfo
 }
 
 type TrackDTO struct {
@@ -0,0 +1,223 @@
+package search
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math"
+
+	sixdegrees "github.com/Jonnymurillo288/MelodyMap/sixDegrees"
+)
+
+// Weight strategies accepted in SearchRequest.Strategy for ModeWeighted
+const (
+	StrategyStrength   = "strength"   // cheapest over 1/shared recordings (default)
+	StrategyPopularity = "popularity" // stay close to the target's popularity (needs cmd/centrality)
+)
+
+const (
+	weightedDefaultDepth  = 4
+	weightedNeighborLimit = 50 // strongest neighbors loaded per artist
+	weightedTracksPerHop  = 50
+)
+
+//
+// ============================================================
+// Weighted ("cheapest chain") search
+// ============================================================
+//
+// The neighborhoods of both ends are copied out of the engine's graph
+// source, half the depth each, and searched with Dijkstra under one of
+// the sixdegrees WeightStrategies. Depth bounds the neighborhood, not the
+// path: the cheapest chain may take more hops than a shortest one.
+
+// weightStrategy maps a strategy name to its sixdegrees implementation.
+func weightStrategy(name string) (sixdegrees.WeightStrategy, bool) {
+	switch name {
+	case "", StrategyStrength:
+		return sixdegrees.CollabStrengthStrategy{}, true
+	case StrategyPopularity:
+		return sixdegrees.PopularityDiffStrategy{}, true
+	}
+	return nil, false
+}
+
+// SearchWeighted is the weighted counterpart of SearchArtists. Each
+// returned step carries the number of recordings shared on that hop.
+func (e *Engine) SearchWeighted(
+	start, target string,
+	depth, limit int,
+	strategy string,
+) (int,
+	[]Step,
+	string,
+	int,
+	error,
+) {
+
+	if start == "" || target == "" {
+		return 0, nil, "start or target empty", 400, nil
+	}
+	strat, ok := weightStrategy(strategy)
+	if !ok {
+		return 0, nil, "unknown weight strategy", 400, nil
+	}
+	if depth <= 0 {
+		depth = weightedDefaultDepth
+	}
+	if limit <= 0 || limit > weightedNeighborLimit {
+		limit = weightedNeighborLimit
+	}
+
+	ctx := context.Background()
+
+	startArtist, err := e.Graph.Resolve(ctx, start)
+	if err != nil {
+		return 0, nil, "start artist not found", 404, nil
+	}
+	targetArtist, err := e.Graph.Resolve(ctx, target)
+	if err != nil {
+		return 0, nil, "target artist not found", 404, nil
+	}
+	from := ArtistRef{MBID: startArtist.ID, Name: startArtist.Name}
+	to := ArtistRef{MBID: targetArtist.ID, Name: targetArtist.Name}
+
+	sub, err := SnapshotNeighborhood(ctx, e.Graph, []ArtistRef{from, to}, (depth+1)/2, limit, 0)
+	if err != nil {
+		return 0, nil, "graph source failed", 502, err
+	}
+
+	path, err := e.weightedPath(ctx, sub, from, to, strat)
+	if err != nil {
+		return 0, nil, "weighted search failed", 500, err
+	}
+	if path == nil {
+		msg := fmt.Sprintf("no path found between %q and %q within depth %d", start, target, depth)
+		return 0, nil, msg, 404, nil
+	}
+
+	steps, err := stepsForPath(ctx, e.Graph, path, weightedTracksPerHop)
+	if err != nil {
+		return 0, nil, "failed to load hop evidence", 500, err
+	}
+	for i := range steps {
+		if edge := sub.edge(steps[i].FromID, steps[i].ToID); edge != nil {
+			steps[i].SharedCount = edge.shared
+		}
+	}
+
+	return len(steps), steps, "", 200, nil
+}
+
+// weightedPath runs Dijkstra over sub and returns the cheapest chain from
+// start to target, or nil when they are not connected. Edge weights come
+// from strat; the search itself is local because the sixdegrees Dijkstra
+// keys vertices by name, and names repeat.
+func (e *Engine) weightedPath(
+	ctx context.Context,
+	sub *MemoryGraph,
+	start, target ArtistRef,
+	strat sixdegrees.WeightStrategy,
+) ([]ArtistRef, error) {
+
+	if start.MBID == target.MBID {
+		return []ArtistRef{start}, nil
+	}
+
+	verts := make([]*sixdegrees.Artists, len(sub.artists))
+	for i, a := range sub.artists {
+		verts[i] = &sixdegrees.Artists{ID: a.MBID, Name: a.Name}
+	}
+	// MusicBrainz has no popularity; the PageRank percentile stands in
+	if _, ok := strat.(sixdegrees.PopularityDiffStrategy); ok && e.store != nil {
+		if err := e.store.FillPopularity(ctx, verts); err != nil {
+			return nil, err
+		}
+	}
+	edgeContext := func(i, j int) sixdegrees.EdgeContext {
+		return sixdegrees.EdgeContext{SharedCount: sub.adj[i][j].shared}
+	}
+	// the summary table adds recency and compilation flags
+	if e.store != nil {
+		var keys []sixdegrees.EdgeKey
+		for i, nbs := range sub.adj {
+			for j := range nbs {
+				keys = append(keys, sixdegrees.EdgeKey{From: verts[i].ID, To: verts[j].ID})
+			}
+		}
+		contexts, err := e.store.EdgeContexts(ctx, keys)
+		if err != nil {
+			return nil, err
+		}
+		edgeContext = func(i, j int) sixdegrees.EdgeContext {
+			if c, ok := contexts[sixdegrees.EdgeKey{From: verts[i].ID, To: verts[j].ID}]; ok {
+				return c
+			}
+			return sixdegrees.EdgeContext{SharedCount: sub.adj[i][j].shared}
+		}
+	}
+
+	// run from the target so strategies see it as the target, then walk
+	// the tree back from the start
+	src, dst := sub.index[target.MBID], sub.index[start.MBID]
+	dist := make([]float64, len(verts))
+	prev := make([]int, len(verts))
+	for i := range dist {
+		dist[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	dist[src] = 0
+
+	pq := &weightedQueue{{at: src}}
+	for pq.Len() > 0 {
+		it := heap.Pop(pq).(weightedItem)
+		if it.dist > dist[it.at] {
+			continue
+		}
+		if it.at == dst {
+			break
+		}
+		for j := range sub.adj[it.at] {
+			w := strat.Weight(verts[src], verts[it.at], verts[j], edgeContext(it.at, j))
+			if math.IsNaN(w) || math.IsInf(w, 0) || w < 0 {
+				continue
+			}
+			if d := it.dist + w; d < dist[j] {
+				dist[j] = d
+				prev[j] = it.at
+				heap.Push(pq, weightedItem{at: j, dist: d})
+			}
+		}
+	}
+
+	if math.IsInf(dist[dst], 1) {
+		return nil, nil
+	}
+	path := []ArtistRef{start}
+	for at := prev[dst]; at != src; at = prev[at] {
+		if at < 0 || len(path) > len(verts) {
+			return nil, fmt.Errorf("broken shortest-path tree at %s", sub.artists[dst].MBID)
+		}
+		path = append(path, sub.artists[at])
+	}
+	return append(path, target), nil
+}
+
+// weightedQueue is a min-heap of tentative distances for weightedPath.
+type weightedQueue []weightedItem
+
+type weightedItem struct {
+	at   int
+	dist float64
+}
+
+func (q weightedQueue) Len() int           { return len(q) }
+func (q weightedQueue) Less(i, j int) bool { return q[i].dist < q[j].dist }
+func (q weightedQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *weightedQueue) Push(x any)        { *q = append(*q, x.(weightedItem)) }
+func (q *weightedQueue) Pop() any {
+	old := *q
+	it := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return it
+}
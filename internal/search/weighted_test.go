@@ -0,0 +1,64 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func stepNames(steps []Step) []string {
+	var names []string
+	for _, s := range steps {
+		names = append(names, s.To)
+	}
+	return names
+}
+
+func TestSearchWeighted_PrefersStrongerDetour(t *testing.T) {
+	g := FixtureGraph()
+	ctx := context.Background()
+	bravo, _ := g.Resolve(ctx, "Bravo")
+	delta, _ := g.Resolve(ctx, "Delta")
+	// a one-off shortcut that a shortest-path search would take
+	g.AddEdge(ArtistRef{MBID: bravo.ID, Name: bravo.Name}, ArtistRef{MBID: delta.ID, Name: delta.Name}, 1, nil)
+	e := NewEngine(g)
+
+	_, steps, msg, status, err := e.SearchArtists("Alpha", "Echo", 5, 100)
+	if err != nil || status != 200 {
+		t.Fatalf("shortest search failed: %d %q %v", status, msg, err)
+	}
+	if fmt.Sprint(stepNames(steps)) != "[Bravo Delta Echo]" {
+		t.Fatalf("unexpected shortest path %v", stepNames(steps))
+	}
+
+	hops, steps, msg, status, err := e.SearchWeighted("Alpha", "Echo", 4, 0, StrategyStrength)
+	if err != nil || status != 200 {
+		t.Fatalf("weighted search failed: %d %q %v", status, msg, err)
+	}
+	if hops != 4 || fmt.Sprint(stepNames(steps)) != "[Bravo Charlie Delta Echo]" {
+		t.Fatalf("expected the detour through Charlie, got %v", stepNames(steps))
+	}
+	var shared []int
+	for _, s := range steps {
+		shared = append(shared, s.SharedCount)
+	}
+	if fmt.Sprint(shared) != "[5 2 3 1]" {
+		t.Fatalf("unexpected per-hop shared counts %v", shared)
+	}
+}
+
+func TestSearchWeighted_RejectsUnknownStrategy(t *testing.T) {
+	e := NewEngine(FixtureGraph())
+
+	if _, _, _, status, _ := e.SearchWeighted("Alpha", "Echo", 4, 0, "loudest"); status != 400 {
+		t.Fatalf("expected 400, got %d", status)
+	}
+}
+
+func TestSearchWeighted_DepthBoundsNeighborhood(t *testing.T) {
+	e := NewEngine(FixtureGraph())
+
+	if _, _, _, status, _ := e.SearchWeighted("Alpha", "Echo", 2, 0, ""); status != 404 {
+		t.Fatalf("expected 404 within depth 2, got %d", status)
+	}
+}
@@ -0,0 +1,227 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// Search modes accepted in SearchRequest.Mode
+const (
+	ModeShortest = "shortest" // fewest hops (default)
+	ModeWidest   = "widest"   // strongest chain: maximize the weakest hop
+	ModeWeighted = "weighted" // cheapest chain under a weight strategy
+)
+
+const (
+	widestDefaultDepth  = 4
+	widestMaxArtists    = 5000 // artists whose neighbor lists we are willing to load
+	widestNeighborLimit = 500  // strongest neighbors considered per artist
+	widestTracksPerHop  = 50
+)
+
+//
+// ============================================================
+// Widest-path ("strongest chain") search
+// ============================================================
+//
+
+// weightedNeighbor is one edge of the collaboration graph as seen by the
+// widest-path search: a neighbor plus the number of shared recordings.
+type weightedNeighbor struct {
+	ID     string
+	Name   string
+	Shared int
+}
+
+// widestLabel is an immutable record of the best chain found so far to
+// an artist. Labels point at their parent so earlier chains stay intact
+// when a node's best width later improves.
+type widestLabel struct {
+	ID     string
+	Name   string
+	Width  int // bottleneck width of the chain ending here
+	Shared int // shared recordings on the last hop
+	Hops   int
+	Parent *widestLabel
+}
+
+// widestPath finds the path from startID to targetID, using at most
+// maxDepth hops, whose smallest per-hop shared count is as large as
+// possible. Among equally wide paths the one with fewer hops wins.
+//
+// It runs hop-bounded Bellman-Ford rounds: round d only extends chains of
+// d hops, so the result honors maxDepth exactly. Artists whose best width
+// cannot beat the best chain to the target are not expanded.
+func widestPath(
+	startID, targetID string,
+	maxDepth, maxArtists int,
+	neighbors func(id string) ([]weightedNeighbor, error),
+) (*widestLabel, error) {
+
+	if startID == "" || targetID == "" {
+		return nil, fmt.Errorf("start or target missing")
+	}
+
+	best := map[string]*widestLabel{
+		startID: {ID: startID, Width: math.MaxInt},
+	}
+	if startID == targetID {
+		return best[startID], nil
+	}
+
+	loaded := make(map[string][]weightedNeighbor)
+	frontier := []string{startID}
+	var found *widestLabel
+
+	for depth := 0; depth < maxDepth && len(frontier) > 0; depth++ {
+		// freeze this round's labels so updates only use depth-hop chains
+		snap := make([]*widestLabel, len(frontier))
+		for i, id := range frontier {
+			snap[i] = best[id]
+		}
+
+		queued := make(map[string]bool)
+		var next []string
+
+		for _, u := range snap {
+			if found != nil && u.Width <= found.Width {
+				continue
+			}
+
+			nbs, ok := loaded[u.ID]
+			if !ok {
+				if len(loaded) >= maxArtists {
+					continue
+				}
+				var err error
+				nbs, err = neighbors(u.ID)
+				if err != nil {
+					return nil, err
+				}
+				loaded[u.ID] = nbs
+			}
+
+			for _, nb := range nbs {
+				if nb.ID == "" || nb.Shared <= 0 {
+					continue
+				}
+				w := min(u.Width, nb.Shared)
+				if cur, ok := best[nb.ID]; ok && w <= cur.Width {
+					continue
+				}
+
+				l := &widestLabel{
+					ID:     nb.ID,
+					Name:   nb.Name,
+					Width:  w,
+					Shared: nb.Shared,
+					Hops:   u.Hops + 1,
+					Parent: u,
+				}
+				best[nb.ID] = l
+
+				if nb.ID == targetID {
+					found = l
+					continue
+				}
+				if !queued[nb.ID] {
+					queued[nb.ID] = true
+					next = append(next, nb.ID)
+				}
+			}
+		}
+
+		frontier = next
+	}
+
+	return found, nil
+}
+
+// chain flattens a label into start→target order.
+func (l *widestLabel) chain() []*widestLabel {
+	var out []*widestLabel
+	for at := l; at != nil; at = at.Parent {
+		out = append(out, at)
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+// SearchWidest is the widest-path counterpart of SearchArtists. Each
+// returned step carries the number of recordings shared on that hop.
+func (e *Engine) SearchWidest(
+	start, target string,
+	depth, limit int,
+) (int,
+	[]Step,
+	string,
+	int,
+	error,
+) {
+
+	if start == "" || target == "" {
+		return 0, nil, "start or target empty", 400, nil
+	}
+	if depth <= 0 {
+		depth = widestDefaultDepth
+	}
+	if limit <= 0 || limit > widestNeighborLimit {
+		limit = widestNeighborLimit
+	}
+
+	ctx := context.Background()
+
+	startArtist, err := e.Graph.Resolve(ctx, start)
+	if err != nil {
+		return 0, nil, "start artist not found", 404, nil
+	}
+	targetArtist, err := e.Graph.Resolve(ctx, target)
+	if err != nil {
+		return 0, nil, "target artist not found", 404, nil
+	}
+
+	found, err := widestPath(startArtist.ID, targetArtist.ID, depth, widestMaxArtists,
+		func(id string) ([]weightedNeighbor, error) {
+			nbs, err := e.Graph.Neighbors(ctx, id, limit)
+			if err != nil {
+				return nil, err
+			}
+			out := make([]weightedNeighbor, 0, len(nbs))
+			for _, nb := range nbs {
+				out = append(out, weightedNeighbor{
+					ID:     nb.MBID,
+					Name:   nb.Name,
+					Shared: nb.Shared,
+				})
+			}
+			return out, nil
+		})
+	if err != nil {
+		return 0, nil, "widest path search failed", 500, err
+	}
+	if found == nil {
+		msg := fmt.Sprintf("no path found between %q and %q within depth %d", start, target, depth)
+		return 0, nil, msg, 404, nil
+	}
+
+	chain := found.chain()
+	chain[0].Name = startArtist.Name
+
+	path := make([]ArtistRef, len(chain))
+	for i, l := range chain {
+		path[i] = ArtistRef{MBID: l.ID, Name: l.Name}
+	}
+
+	steps, err := stepsForPath(ctx, e.Graph, path, widestTracksPerHop)
+	if err != nil {
+		return 0, nil, "failed to load hop evidence", 500, err
+	}
+	for i := range steps {
+		steps[i].SharedCount = chain[i+1].Shared
+	}
+
+	return len(steps), steps, "", 200, nil
+}
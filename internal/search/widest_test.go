@@ -0,0 +1,108 @@
+package search
+
+import (
+	"fmt"
+	"testing"
+)
+
+// fixtureNeighbors builds an undirected weighted graph from {a, b}: w edges.
+func fixtureNeighbors(edges map[[2]string]int) func(id string) ([]weightedNeighbor, error) {
+	adj := make(map[string][]weightedNeighbor)
+	for e, w := range edges {
+		adj[e[0]] = append(adj[e[0]], weightedNeighbor{ID: e[1], Name: e[1], Shared: w})
+		adj[e[1]] = append(adj[e[1]], weightedNeighbor{ID: e[0], Name: e[0], Shared: w})
+	}
+	return func(id string) ([]weightedNeighbor, error) {
+		return adj[id], nil
+	}
+}
+
+func chainIDs(l *widestLabel) []string {
+	var ids []string
+	for _, c := range l.chain() {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func TestWidestPath_PrefersStrongerLongerChain(t *testing.T) {
+	nb := fixtureNeighbors(map[[2]string]int{
+		{"A", "T"}: 1, // one-off feature credit
+		{"A", "B"}: 12,
+		{"B", "T"}: 9,
+	})
+
+	got, err := widestPath("A", "T", 4, 100, nb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil {
+		t.Fatalf("expected a path")
+	}
+	if fmt.Sprint(chainIDs(got)) != "[A B T]" {
+		t.Fatalf("expected A->B->T, got %v", chainIDs(got))
+	}
+	if got.Width != 9 {
+		t.Fatalf("expected bottleneck 9, got %d", got.Width)
+	}
+	chain := got.chain()
+	if chain[1].Shared != 12 || chain[2].Shared != 9 {
+		t.Fatalf("unexpected per-hop shared counts: %d, %d", chain[1].Shared, chain[2].Shared)
+	}
+}
+
+func TestWidestPath_RespectsDepthLimit(t *testing.T) {
+	nb := fixtureNeighbors(map[[2]string]int{
+		{"A", "T"}: 2,
+		{"A", "B"}: 10,
+		{"B", "C"}: 10,
+		{"C", "T"}: 10,
+	})
+
+	got, err := widestPath("A", "T", 2, 100, nb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || fmt.Sprint(chainIDs(got)) != "[A T]" {
+		t.Fatalf("expected direct hop under depth 2, got %v", got)
+	}
+
+	got, err = widestPath("A", "T", 3, 100, nb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Width != 10 || got.Hops != 3 {
+		t.Fatalf("expected 3-hop chain of width 10, got %+v", got)
+	}
+}
+
+func TestWidestPath_TieBreaksOnFewerHops(t *testing.T) {
+	nb := fixtureNeighbors(map[[2]string]int{
+		{"A", "T"}: 5,
+		{"A", "B"}: 5,
+		{"B", "T"}: 5,
+	})
+
+	got, err := widestPath("A", "T", 4, 100, nb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Hops != 1 {
+		t.Fatalf("expected direct hop on width tie, got %+v", got)
+	}
+}
+
+func TestWidestPath_NoPath(t *testing.T) {
+	nb := fixtureNeighbors(map[[2]string]int{
+		{"A", "B"}: 3,
+		{"C", "T"}: 3,
+	})
+
+	got, err := widestPath("A", "T", 6, 100, nb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Fatalf("expected no path, got %v", chainIDs(got))
+	}
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/search"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+// GET /api/artists/{mbid}?top=10
+// ------------------------------------------------------------
+// Metadata, tags and collaboration counts for one artist, for hover
+// panels and artist pages.
+func (srv *server) artistProfileHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	mbid, ok := srv.canonicalPathMBID(w, r)
+	if !ok {
+		return
+	}
+	top, _ := strconv.Atoi(r.URL.Query().Get("top"))
+
+	s := srv.store
+
+	p, err := s.ArtistProfile(r.Context(), mbid, top)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"artist_not_found"}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("artist profile failed for %s: %v", mbid, err)
+		http.Error(w, `{"error":"profile_failed"}`, http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(p)
+}
+
+// ------------------------------------------------------------
+// GET /api/artists/{mbid}/profile/distances?depth=<n>
+// ------------------------------------------------------------
+// Returns the cached profile when one exists, otherwise the ID of the
+// background job computing it (poll /api/search/status?jobID=).
+func (srv *server) artistDistancesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	mbid, ok := srv.canonicalPathMBID(w, r)
+	if !ok {
+		return
+	}
+
+	depth, _ := strconv.Atoi(r.URL.Query().Get("depth"))
+	depth = search.ClampProfileDepth(depth)
+
+	if p, ok := search.CachedDistanceProfile(mbid, depth); ok {
+		json.NewEncoder(w).Encode(map[string]any{
+			"status": "finished",
+			"result": p,
+		})
+		return
+	}
+
+	job := srv.store.StartDistanceProfile(mbid, depth)
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{
+		"jobID": job.ID,
+	})
+}
+
+const (
+	networkDefaultDepth = 2
+	networkMaxDepth     = 3
+	networkDefaultLimit = 25
+	networkMaxLimit     = 100
+	networkMaxNodes     = 2000
+	networkTracksPerHop = 10
+)
+
+// ------------------------------------------------------------
+// GET /api/artists/{mbid}/network?depth=2&limit=25&tracks=1
+// ------------------------------------------------------------
+// limit is the top-k neighbors kept per artist, by shared recordings.
+// Optional &format=graphml|gexf|dot exports the subgraph instead.
+func (srv *server) artistNetworkHandler(w http.ResponseWriter, r *http.Request) {
+	mbid := r.PathValue("mbid")
+	q := r.URL.Query()
+
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+
+	depth, _ := strconv.Atoi(q.Get("depth"))
+	if depth <= 0 {
+		depth = networkDefaultDepth
+	}
+	if depth > networkMaxDepth {
+		depth = networkMaxDepth
+	}
+
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = networkDefaultLimit
+	}
+	if limit > networkMaxLimit {
+		limit = networkMaxLimit
+	}
+
+	withTracks := q.Get("tracks") == "1" || q.Get("tracks") == "true"
+
+	graph := srv.engine.Graph
+
+	center, err := graph.Resolve(r.Context(), mbid)
+	if err != nil {
+		http.Error(w, `{"error":"artist_not_found"}`, http.StatusNotFound)
+		return
+	}
+
+	gb := NewGraphBuilder(graph)
+	gb.TopK = limit
+	gb.MaxNodes = networkMaxNodes
+	if withTracks {
+		gb.TracksPerEdge = networkTracksPerHop
+	}
+
+	g, err := gb.BuildFrom(
+		r.Context(),
+		center,
+		"",
+		depth,
+		limit,
+		false,
+	)
+	if err != nil {
+		log.Printf("network build failed for %s: %v", mbid, err)
+		http.Error(w, `{"error":"network_failed"}`, http.StatusInternalServerError)
+		return
+	}
+
+	if format != "" {
+		writeGraphExport(w, format, "network-"+center.ID, g.Export(center.ID))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(g.Network(center.ID, depth))
+}
+
+// ------------------------------------------------------------
+// GET /api/artists/{mbid}/profile/centrality
+// ------------------------------------------------------------
+func (srv *server) artistCentralityHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	mbid, ok := srv.canonicalPathMBID(w, r)
+	if !ok {
+		return
+	}
+
+	s := srv.store
+
+	c, err := s.GetCentrality(r.Context(), mbid)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"no_centrality"}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, `{"error":"centrality_lookup_failed"}`, http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"mbid":       mbid,
+		"centrality": c,
+	})
+}
+
+// ------------------------------------------------------------
+// GET /api/artists/{mbid}/predicted-collaborators?limit=20
+// ------------------------------------------------------------
+// Ranks artists two hops away by Adamic-Adar over shared collaborators,
+// returning the shared neighbors that justify each score.
+const (
+	artistListDefaultLimit = 20
+	artistListMaxLimit     = 100
+)
+
+func (srv *server) predictedCollaboratorsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	mbid, ok := srv.canonicalPathMBID(w, r)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = artistListDefaultLimit
+	}
+	if limit > artistListMaxLimit {
+		limit = artistListMaxLimit
+	}
+
+	s := srv.store
+
+	preds, err := s.PredictCollaborators(r.Context(), mbid, limit)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"artist_not_found"}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("link prediction failed for %s: %v", mbid, err)
+		http.Error(w, `{"error":"prediction_failed"}`, http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"mbid":        mbid,
+		"predictions": preds,
+	})
+}
+
+// ------------------------------------------------------------
+// GET /api/artists/{mbid}/similar?limit=20
+// ------------------------------------------------------------
+// Artists whose collaborators overlap this one's, from the LSH index
+// built by cmd/similarity.
+func (srv *server) similarArtistsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	mbid, ok := srv.canonicalPathMBID(w, r)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = artistListDefaultLimit
+	}
+	if limit > artistListMaxLimit {
+		limit = artistListMaxLimit
+	}
+
+	s := srv.store
+
+	similar, err := s.SimilarArtists(r.Context(), mbid, limit)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"artist_not_found"}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("similar artists failed for %s: %v", mbid, err)
+		http.Error(w, `{"error":"similarity_failed"}`, http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"mbid":    mbid,
+		"similar": similar,
+	})
+}
+
+// ------------------------------------------------------------
+// GET /api/artists/resolve?name=<name>&limit=5
+// ------------------------------------------------------------
+// Ranked candidates for a typed name, with disambiguation comments and
+// the confidence of the best match.
+func (srv *server) resolveArtistHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	q := r.URL.Query()
+
+	name := strings.TrimSpace(q.Get("name"))
+	if name == "" {
+		http.Error(w, `{"error":"missing_name"}`, http.StatusBadRequest)
+		return
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = search.ResolveDefaultLimit
+	}
+	if limit > artistListMaxLimit {
+		limit = artistListMaxLimit
+	}
+
+	s := srv.store
+
+	res, err := s.ResolveArtist(r.Context(), name, limit)
+	if err != nil {
+		log.Printf("resolve failed for %q: %v", name, err)
+		http.Error(w, `{"error":"resolve_failed"}`, http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(res)
+}
+
+// ------------------------------------------------------------
+// GET /api/artists/suggest?q=<prefix>&limit=10
+// ------------------------------------------------------------
+// Autocomplete over every MusicBrainz artist name and alias.
+func (srv *server) suggestArtistsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	q := r.URL.Query()
+
+	query := strings.TrimSpace(q.Get("q"))
+	if len([]rune(query)) < search.SuggestMinQuery {
+		json.NewEncoder(w).Encode(map[string]any{"query": query, "artists": []search.ArtistSuggestion{}})
+		return
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+
+	s := srv.store
+
+	artists, err := s.SuggestArtists(r.Context(), query, limit)
+	if err != nil {
+		log.Printf("suggest failed for %q: %v", query, err)
+		http.Error(w, `{"error":"suggest_failed"}`, http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"query":   query,
+		"artists": artists,
+	})
+}
+
+// canonicalPathMBID reads the {mbid} path value and follows MusicBrainz
+// merge redirects, so old links keep working and responses carry the
+// current MBID. On failure it has already written the error response.
+func (srv *server) canonicalPathMBID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	mbid := r.PathValue("mbid")
+	if _, err := uuid.Parse(mbid); err != nil {
+		http.Error(w, `{"error":"invalid_mbid"}`, http.StatusBadRequest)
+		return "", false
+	}
+
+	a, err := srv.store.ResolveArtistMBID(r.Context(), mbid)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"artist_not_found"}`, http.StatusNotFound)
+		return "", false
+	}
+	if err != nil {
+		log.Printf("mbid lookup failed for %s: %v", mbid, err)
+		http.Error(w, `{"error":"db_unavailable"}`, http.StatusInternalServerError)
+		return "", false
+	}
+	return a.ID, true
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/jobs"
+	"github.com/Jonnymurillo288/MelodyMap/internal/search"
+)
+
+// ------------------------------------------------------------
+// GET /api/artists/{mbid}/community
+// ------------------------------------------------------------
+// Which scene is this artist in?
+func (srv *server) artistCommunityHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	mbid, ok := srv.canonicalPathMBID(w, r)
+	if !ok {
+		return
+	}
+
+	s := srv.store
+
+	ctx := r.Context()
+
+	ids, err := s.CommunitiesForArtists(ctx, []string{mbid})
+	if err != nil {
+		http.Error(w, `{"error":"community_lookup_failed"}`, http.StatusInternalServerError)
+		return
+	}
+	id, ok := ids[mbid]
+	if !ok {
+		http.Error(w, `{"error":"no_community"}`, http.StatusNotFound)
+		return
+	}
+
+	cs, err := s.GetCommunities(ctx, []int{id})
+	if err != nil {
+		http.Error(w, `{"error":"community_lookup_failed"}`, http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"mbid":        mbid,
+		"communityID": id,
+		"community":   cs[id], // nil for singleton communities without a summary
+	})
+}
+
+// ------------------------------------------------------------
+// GET /api/search/communities?jobID=<jobID>
+// ------------------------------------------------------------
+// Which communities does a found path cross?
+func (srv *server) pathCommunitiesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	job, ok := jobs.Manager.Get(r.URL.Query().Get("jobID"))
+	if !ok {
+		http.Error(w, `{"error":"job_not_found"}`, http.StatusNotFound)
+		return
+	}
+	result, ok := job.Result.(search.SearchResponse)
+	if job.Status != jobs.StatusFinished || !ok {
+		http.Error(w, `{"error":"job_not_finished"}`, http.StatusBadRequest)
+		return
+	}
+
+	type pathArtist struct {
+		MBID        string `json:"mbid"`
+		Name        string `json:"name"`
+		CommunityID *int   `json:"communityID"`
+	}
+
+	var artists []pathArtist
+	var mbids []string
+	for i, st := range result.Path {
+		if i == 0 {
+			artists = append(artists, pathArtist{MBID: st.FromID, Name: st.From})
+			mbids = append(mbids, st.FromID)
+		}
+		artists = append(artists, pathArtist{MBID: st.ToID, Name: st.To})
+		mbids = append(mbids, st.ToID)
+	}
+
+	s := srv.store
+
+	ctx := r.Context()
+
+	byMBID, err := s.CommunitiesForArtists(ctx, mbids)
+	if err != nil {
+		http.Error(w, `{"error":"community_lookup_failed"}`, http.StatusInternalServerError)
+		return
+	}
+
+	var ids []int
+	crossings := 0
+	for i := range artists {
+		id, ok := byMBID[artists[i].MBID]
+		if !ok {
+			continue
+		}
+		artists[i].CommunityID = &id
+		ids = append(ids, id)
+		if i > 0 && artists[i-1].CommunityID != nil && *artists[i-1].CommunityID != id {
+			crossings++
+		}
+	}
+
+	cs, err := s.GetCommunities(ctx, ids)
+	if err != nil {
+		http.Error(w, `{"error":"community_lookup_failed"}`, http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"artists":     artists,
+		"communities": cs,
+		"crossings":   crossings,
+	})
+}
+
+// ------------------------------------------------------------
+// GET /api/communities?limit=20
+// ------------------------------------------------------------
+func (srv *server) topCommunitiesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	s := srv.store
+
+	cs, err := s.TopCommunities(r.Context(), limit)
+	if err != nil {
+		http.Error(w, `{"error":"community_lookup_failed"}`, http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(cs)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/export"
+)
+
+// Export converts the graph into the neutral export model.
+func (g *Graph) Export(centerID string) *export.Graph {
+	n := g.Network(centerID, 0)
+
+	out := &export.Graph{
+		Nodes: make([]export.Node, 0, len(n.Nodes)),
+		Edges: make([]export.Edge, 0, len(n.Edges)),
+	}
+	for _, nd := range n.Nodes {
+		out.Nodes = append(out.Nodes, export.Node{ID: nd.ID, Name: nd.Name})
+	}
+	for _, e := range n.Edges {
+		out.Edges = append(out.Edges, export.Edge{
+			Source: e.Source,
+			Target: e.Target,
+			Weight: e.Weight,
+			Tracks: e.Tracks,
+		})
+	}
+	return out
+}
+
+// writeGraphExport serializes g as a downloadable file in the requested format.
+func writeGraphExport(w http.ResponseWriter, format, filename string, g *export.Graph) {
+	ctype, ext := export.ContentType(format)
+	w.Header().Set("Content-Type", ctype)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, ext))
+
+	if err := export.Write(w, format, g); err != nil {
+		log.Printf("export %s failed: %v", format, err)
+	}
+}
+
+// exportFormat reads ?format= and rejects anything we can't serialize.
+// It returns "" for the default JSON response.
+func exportFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	format := r.URL.Query().Get("format")
+	if format == "" || format == export.FormatJSON {
+		return "", true
+	}
+	if !export.IsGraphFormat(format) {
+		http.Error(w, "unsupported format", http.StatusBadRequest)
+		return "", false
+	}
+	return format, true
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/game"
+	"github.com/Jonnymurillo288/MelodyMap/internal/search"
+)
+
+const (
+	gamePlayerMaxLen      = 40
+	gameScoresDefaultSize = 20
+	gameScoresMaxSize     = 100
+)
+
+// puzzleDay reads ?date=YYYY-MM-DD (default today, UTC). Future days are
+// rejected so nobody can peek at tomorrow's pair.
+func puzzleDay(w http.ResponseWriter, raw string) (time.Time, bool) {
+	today := game.Day(time.Now())
+	if raw == "" {
+		return today, true
+	}
+	day, err := time.Parse(game.DateLayout, raw)
+	if err != nil {
+		http.Error(w, `{"error":"bad_date"}`, http.StatusBadRequest)
+		return time.Time{}, false
+	}
+	if day.After(today) {
+		http.Error(w, `{"error":"future_date"}`, http.StatusBadRequest)
+		return time.Time{}, false
+	}
+	return day, true
+}
+
+// dailyPuzzle loads (or creates) the puzzle, writing the error response
+// itself when that fails.
+func dailyPuzzle(ctx context.Context, w http.ResponseWriter, s *search.Store, day time.Time) (*search.Puzzle, bool) {
+	p, err := s.DailyPuzzle(ctx, day, game.BandFromEnv())
+	if errors.Is(err, search.ErrNoPuzzle) {
+		http.Error(w, `{"error":"no_puzzle_available"}`, http.StatusServiceUnavailable)
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("daily puzzle failed: %v", err)
+		http.Error(w, `{"error":"puzzle_lookup_failed"}`, http.StatusInternalServerError)
+		return nil, false
+	}
+	return p, true
+}
+
+// ------------------------------------------------------------
+// GET /api/game/daily?date=YYYY-MM-DD
+// ------------------------------------------------------------
+// The optimal path is only revealed once the day is over.
+func (srv *server) dailyPuzzleHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	day, ok := puzzleDay(w, r.URL.Query().Get("date"))
+	if !ok {
+		return
+	}
+
+	s := srv.store
+
+	p, ok := dailyPuzzle(r.Context(), w, s, day)
+	if !ok {
+		return
+	}
+	if !day.Before(game.Day(time.Now())) {
+		p.OptimalPath = nil
+	}
+
+	json.NewEncoder(w).Encode(p)
+}
+
+type dailySubmission struct {
+	Date   string   `json:"date"`
+	Player string   `json:"player"`
+	Chain  []string `json:"chain"` // artist names or MBIDs, start to target
+}
+
+// ------------------------------------------------------------
+// POST /api/game/daily/submit
+// ------------------------------------------------------------
+// Validates every hop and scores against the optimal length. A player
+// gets one attempt per day; later submissions return the first attempt's
+// result. Player names are not authenticated, so the optimal path stays
+// hidden until the day is over, as on the puzzle itself.
+func (srv *server) dailySubmitHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req dailySubmission
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"bad_request"}`, http.StatusBadRequest)
+		return
+	}
+	req.Player = strings.TrimSpace(req.Player)
+	if req.Player == "" || len(req.Player) > gamePlayerMaxLen {
+		http.Error(w, `{"error":"bad_player"}`, http.StatusBadRequest)
+		return
+	}
+	if len(req.Chain) > game.MaxChainLength {
+		http.Error(w, `{"error":"chain_too_long"}`, http.StatusBadRequest)
+		return
+	}
+
+	day, ok := puzzleDay(w, req.Date)
+	if !ok {
+		return
+	}
+
+	s := srv.store
+
+	p, ok := dailyPuzzle(r.Context(), w, s, day)
+	if !ok {
+		return
+	}
+
+	res, err := s.SubmitDaily(r.Context(), p, req.Player, req.Chain)
+	var unknown *search.UnknownArtistError
+	if errors.As(err, &unknown) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"error": "unknown_artist",
+			"index": unknown.Index,
+			"entry": unknown.Entry,
+		})
+		return
+	}
+	if err != nil {
+		log.Printf("daily submit failed: %v", err)
+		http.Error(w, `{"error":"submit_failed"}`, http.StatusInternalServerError)
+		return
+	}
+	if !day.Before(game.Day(time.Now())) {
+		res.OptimalPath = nil
+	}
+
+	json.NewEncoder(w).Encode(res)
+}
+
+// ------------------------------------------------------------
+// GET /api/game/daily/scores?date=YYYY-MM-DD&limit=20
+// ------------------------------------------------------------
+func (srv *server) dailyScoresHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	q := r.URL.Query()
+
+	day, ok := puzzleDay(w, q.Get("date"))
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = gameScoresDefaultSize
+	}
+	if limit > gameScoresMaxSize {
+		limit = gameScoresMaxSize
+	}
+
+	s := srv.store
+
+	scores, err := s.DailyScores(r.Context(), day, limit)
+	if err != nil {
+		log.Printf("daily scores failed: %v", err)
+		http.Error(w, `{"error":"scores_lookup_failed"}`, http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"date":   day.Format(game.DateLayout),
+		"scores": scores,
+	})
+}
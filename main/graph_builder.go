@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"sort"
 
+	"github.com/Jonnymurillo288/MelodyMap/internal/search"
 	sixdegrees "github.com/Jonnymurillo288/MelodyMap/sixDegrees"
 )
 
@@ -10,113 +14,202 @@ import (
 type Graph struct {
 	Nodes      map[string]*sixdegrees.Artists
 	Edges      map[string][]string
-	TrackEdges map[string]map[string]sixdegrees.Track
+	TrackEdges map[string]map[string][]sixdegrees.Track
+	Weights    map[string]map[string]int // shared recordings per edge
+	Depth      map[string]int            // hops from the start artist
 }
 
-type NeighborProvider func(
-	a *sixdegrees.Artists,
-	limit int,
-	verbose bool,
-) ([]*sixdegrees.Artists, int, error)
-
 type GraphBuilder struct {
-	NeighborFn NeighborProvider
+	Source search.GraphSource
+
+	// TracksPerEdge loads up to this many tracks of evidence for every
+	// kept edge (0 = none).
+	TracksPerEdge int
+
+	// TopK keeps only the K strongest neighbors of each artist (0 = all),
+	// so hubs with thousands of collaborators don't explode the graph.
+	TopK int
+	// MaxNodes stops expansion once the graph holds this many artists (0 = no cap).
+	MaxNodes int
 }
 
-func NewGraphBuilder(np NeighborProvider) *GraphBuilder {
-	return &GraphBuilder{NeighborFn: np}
+func NewGraphBuilder(src search.GraphSource) *GraphBuilder {
+	return &GraphBuilder{Source: src}
 }
 
-// BuildFrom builds the graph via BFS from the start artist.
+// graphBatchSize is how many frontier artists share one NeighborsBatch call.
+const graphBatchSize = 50
+
+// BuildFrom builds the graph via BFS from the start artist, a level at a
+// time with one NeighborsBatch call per batch of frontier artists.
 // EARLY EXIT: stops building the moment we discover the targetID.
+// An empty targetID builds the whole neighborhood up to maxDepth.
+// Expansion stops once the graph holds MaxNodes artists.
 func (gb *GraphBuilder) BuildFrom(
+	ctx context.Context,
 	start *sixdegrees.Artists,
 	targetID string,
 	maxDepth, perArtistLimit int,
 	verbose bool,
 ) (*Graph, error) {
 
-	if gb.NeighborFn == nil {
-		return nil, fmt.Errorf("GraphBuilder.NeighborFn is nil")
+	if gb.Source == nil {
+		return nil, fmt.Errorf("GraphBuilder.Source is nil")
 	}
 
 	g := &Graph{
 		Nodes:      make(map[string]*sixdegrees.Artists),
 		Edges:      make(map[string][]string),
-		TrackEdges: make(map[string]map[string]sixdegrees.Track),
-	}
-
-	type frontierItem struct {
-		Artist *sixdegrees.Artists
-		Depth  int
+		TrackEdges: make(map[string]map[string][]sixdegrees.Track),
+		Weights:    make(map[string]map[string]int),
+		Depth:      make(map[string]int),
 	}
 
-	queue := []frontierItem{{Artist: start, Depth: 0}}
-	seen := map[string]bool{start.ID: true}
 	g.Nodes[start.ID] = start
+	g.Depth[start.ID] = 0
+	frontier := []*sixdegrees.Artists{start}
+	full := func() bool { return gb.MaxNodes > 0 && len(g.Nodes) >= gb.MaxNodes }
 
 	if verbose {
-		fmt.Println("GraphBuilder: starting BFS graph construction")
+		log.Printf("GraphBuilder: starting BFS graph construction")
 	}
 
-	for len(queue) > 0 {
-		item := queue[0]
-		queue = queue[1:]
-
-		if item.Depth >= maxDepth && maxDepth > 0 {
-			continue
-		}
+	for depth := 0; len(frontier) > 0 && (maxDepth <= 0 || depth < maxDepth); depth++ {
+		var next []*sixdegrees.Artists
 
-		neighbors, status, err := gb.NeighborFn(item.Artist, perArtistLimit, verbose)
-		if status == 429 {
-			return nil, fmt.Errorf("external rate limit during graph build")
-		}
-		if err != nil {
-			return nil, fmt.Errorf("NeighborProvider failed for %s: %w",
-				item.Artist.Name, err)
-		}
-
-		// Add edges
-		for _, nb := range neighbors {
-			if nb == nil || nb.ID == "" {
-				continue
+		for i := 0; i < len(frontier) && !full(); i += graphBatchSize {
+			batch := frontier[i:min(i+graphBatchSize, len(frontier))]
+			ids := make([]string, len(batch))
+			for j, a := range batch {
+				ids[j] = a.ID
 			}
 
-			// Track node
-			if _, ok := g.Nodes[nb.ID]; !ok {
-				g.Nodes[nb.ID] = nb
+			nbs, err := gb.Source.NeighborsBatch(ctx, ids, perArtistLimit)
+			if err != nil {
+				return nil, fmt.Errorf("neighbors failed at depth %d: %w", depth, err)
 			}
 
-			// Add adjacency
-			g.Edges[item.Artist.ID] = append(g.Edges[item.Artist.ID], nb.ID)
+			for _, artist := range batch {
+				if full() {
+					break
+				}
 
-			// EARLY EXIT if we've just found the target
-			if nb.ID == targetID {
-				if verbose {
-					fmt.Printf("GraphBuilder: discovered target %s at depth %d\n",
-						targetID, item.Depth+1)
+				// keep the edges that fit, up to the target if it shows up
+				var kept []*NeighborEdge
+				found := false
+				for _, edge := range gb.rank(nbs[artist.ID]) {
+					nb := edge.Artist
+					if nb.ID == "" {
+						continue
+					}
+					if _, ok := g.Nodes[nb.ID]; !ok {
+						if full() {
+							continue
+						}
+						g.Nodes[nb.ID] = nb
+						g.Depth[nb.ID] = depth + 1
+						next = append(next, nb)
+					}
+					kept = append(kept, edge)
+					if nb.ID == targetID {
+						found = true
+						break
+					}
+				}
+
+				if err := gb.loadEvidence(ctx, artist, kept); err != nil {
+					return nil, fmt.Errorf("evidence failed for %s: %w", artist.Name, err)
+				}
+				for _, edge := range kept {
+					g.Edges[artist.ID] = append(g.Edges[artist.ID], edge.Artist.ID)
+					g.addEdgeData(artist.ID, edge.Artist.ID, edge)
 				}
-				return g, nil
-			}
 
-			// enqueue new node
-			if !seen[nb.ID] {
-				seen[nb.ID] = true
-				queue = append(queue, frontierItem{
-					Artist: nb,
-					Depth:  item.Depth + 1,
-				})
+				// EARLY EXIT if we've just found the target
+				if found {
+					if verbose {
+						log.Printf("GraphBuilder: discovered target %s at depth %d",
+							targetID, depth+1)
+					}
+					return g, nil
+				}
 			}
 		}
+
+		frontier = next
 	}
 
 	if verbose {
-		fmt.Println("GraphBuilder: completed full BFS graph construction")
+		log.Printf("GraphBuilder: completed full BFS graph construction")
 	}
 
 	return g, nil
 }
 
+// rank orders an artist's neighbors by shared recordings and keeps the
+// TopK strongest.
+func (gb *GraphBuilder) rank(nbs []search.Neighbor) []*NeighborEdge {
+	out := make([]*NeighborEdge, 0, len(nbs))
+	for _, nb := range nbs {
+		out = append(out, &NeighborEdge{
+			Artist: sixdegrees.CreateArtists(nb.Name, nb.MBID),
+			Link:   "track-collaboration",
+			Shared: nb.Shared,
+		})
+	}
+	return pruneTopK(out, gb.TopK)
+}
+
+// loadEvidence fills the track evidence of an artist's kept edges, if
+// asked, with one lookup for all of them.
+func (gb *GraphBuilder) loadEvidence(ctx context.Context, artist *sixdegrees.Artists, edges []*NeighborEdge) error {
+	if gb.TracksPerEdge <= 0 || len(edges) == 0 {
+		return nil
+	}
+	from := search.ArtistRef{MBID: artist.ID, Name: artist.Name}
+	to := make([]search.ArtistRef, len(edges))
+	for i, edge := range edges {
+		to[i] = search.ArtistRef{MBID: edge.Artist.ID, Name: edge.Artist.Name}
+	}
+	evidence, err := search.EdgeEvidenceFor(ctx, gb.Source, from, to, gb.TracksPerEdge)
+	if err != nil {
+		return err
+	}
+	for _, edge := range edges {
+		for _, t := range evidence[edge.Artist.ID] {
+			edge.Track = append(edge.Track, search.TrackFromInfo(t))
+		}
+	}
+	return nil
+}
+
+// addEdgeData records the weight and track evidence of an edge.
+func (g *Graph) addEdgeData(fromID, toID string, edge *NeighborEdge) {
+	if g.Weights[fromID] == nil {
+		g.Weights[fromID] = make(map[string]int)
+	}
+	g.Weights[fromID][toID] = edge.Shared
+
+	if len(edge.Track) > 0 {
+		if g.TrackEdges[fromID] == nil {
+			g.TrackEdges[fromID] = make(map[string][]sixdegrees.Track)
+		}
+		g.TrackEdges[fromID][toID] = edge.Track
+	}
+}
+
+// pruneTopK keeps the k neighbors with the most shared recordings.
+func pruneTopK(in []*NeighborEdge, k int) []*NeighborEdge {
+	if k <= 0 || len(in) <= k {
+		return in
+	}
+	out := append([]*NeighborEdge(nil), in...)
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Shared > out[j].Shared
+	})
+	return out[:k]
+}
+
 // BFSOnGraph runs a pure BFS on the already-built Graph.
 // No DB calls, no API calls — only adjacency traversal.
 func BFSOnGraph(
@@ -165,8 +258,8 @@ func BFSOnGraph(
 
 				// Store track edge if available
 				if g.TrackEdges[item.ID] != nil {
-					if t, ok := g.TrackEdges[item.ID][nbID]; ok {
-						trackTo[nbID] = t
+					if ts := g.TrackEdges[item.ID][nbID]; len(ts) > 0 {
+						trackTo[nbID] = ts[0]
 					}
 				}
 
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -51,6 +52,27 @@ func exists(path string) bool {
 	return err == nil
 }
 
+// server holds what the HTTP handlers share: one Store, opened at startup,
+// whose pool serves every request, and the search engine over the graph
+// source chosen by GRAPH_SOURCE. store is nil when a non-Postgres graph
+// source runs without a database.
+type server struct {
+	store  *search.Store
+	engine *search.Engine
+}
+
+// withStore answers 503 on routes that need the database when the server
+// runs on its graph source alone.
+func (srv *server) withStore(h http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if srv.store == nil {
+			http.Error(w, `{"error":"db_unavailable"}`, http.StatusServiceUnavailable)
+			return
+		}
+		h(w, r)
+	})
+}
+
 // tokenAuth enforces the short-lived anti-scrape token on API routes.
 func tokenAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -72,6 +94,24 @@ func main() {
 	fmt.Println("CONFIG FILE CHECK:", info, err)
 	fmt.Printf("AUTHCONFIG RAW: %+v\n", secret.AuthConfig)
 
+	store, err := search.Open("")
+	switch {
+	case err == nil:
+		defer store.Close()
+		if err := store.MigrateGame(context.Background()); err != nil {
+			log.Printf("game migrate failed: %v", err)
+		}
+	case search.GraphSourceUsesDB():
+		log.Fatalf("open db: %v", err)
+	default:
+		log.Printf("open db failed, serving searches only: %v", err)
+	}
+	graph, err := search.GraphSourceFromEnv(store)
+	if err != nil {
+		log.Fatalf("graph source: %v", err)
+	}
+	srv := &server{store: store, engine: search.NewEngine(graph)}
+
 	mux := http.NewServeMux()
 
 	// static
@@ -85,12 +125,37 @@ func main() {
 		t.Execute(w, data)
 	})
 
+	// ML / analysis page
+	mux.HandleFunc("/ml", func(w http.ResponseWriter, r *http.Request) {
+		tok, _ := auth.CreateToken()
+		data := struct{ Token string }{Token: tok}
+		t := template.Must(template.ParseFiles(filepath.Join(root, "templates", "ml_page.html")))
+		t.Execute(w, data)
+	})
+
 	// search API (background)
 	// --- PROTECTED ROUTES ---
-	mux.Handle("/createPlaylist", tokenAuth(http.HandlerFunc(createPlaylistHandler)))
-	mux.Handle("/api/search/start", tokenAuth(http.HandlerFunc(startSearchHandler)))
-	mux.Handle("/api/search/status", tokenAuth(http.HandlerFunc(searchStatusHandler)))
+	mux.Handle("/createPlaylist", tokenAuth(http.HandlerFunc(srv.createPlaylistHandler)))
+	mux.Handle("/api/search/start", tokenAuth(http.HandlerFunc(srv.startSearchHandler)))
+	mux.Handle("/api/search/status", tokenAuth(http.HandlerFunc(srv.searchStatusHandler)))
 	mux.Handle("/lookup", tokenAuth(http.HandlerFunc(handleLookup)))
+	mux.Handle("GET /api/artists/resolve", tokenAuth(srv.withStore(srv.resolveArtistHandler)))
+	mux.Handle("GET /api/artists/suggest", tokenAuth(srv.withStore(srv.suggestArtistsHandler)))
+	mux.Handle("GET /api/artists/{mbid}", tokenAuth(srv.withStore(srv.artistProfileHandler)))
+	mux.Handle("GET /api/artists/{mbid}/profile/distances", tokenAuth(srv.withStore(srv.artistDistancesHandler)))
+	mux.Handle("GET /api/artists/{mbid}/profile/centrality", tokenAuth(srv.withStore(srv.artistCentralityHandler)))
+	mux.Handle("GET /api/artists/{mbid}/network", tokenAuth(http.HandlerFunc(srv.artistNetworkHandler)))
+	mux.Handle("GET /api/artists/{mbid}/community", tokenAuth(srv.withStore(srv.artistCommunityHandler)))
+	mux.Handle("GET /api/artists/{mbid}/predicted-collaborators", tokenAuth(srv.withStore(srv.predictedCollaboratorsHandler)))
+	mux.Handle("GET /api/artists/{mbid}/similar", tokenAuth(srv.withStore(srv.similarArtistsHandler)))
+	mux.Handle("GET /api/search/communities", tokenAuth(srv.withStore(srv.pathCommunitiesHandler)))
+	mux.Handle("GET /api/communities", tokenAuth(srv.withStore(srv.topCommunitiesHandler)))
+	mux.Handle("GET /api/pairs/hardest", tokenAuth(srv.withStore(srv.hardestPairsHandler)))
+	mux.Handle("GET /api/pairs/lookup", tokenAuth(srv.withStore(srv.pairLookupHandler)))
+	mux.Handle("GET /api/game/daily", tokenAuth(srv.withStore(srv.dailyPuzzleHandler)))
+	mux.Handle("POST /api/game/daily/submit", tokenAuth(srv.withStore(srv.dailySubmitHandler)))
+	mux.Handle("GET /api/game/daily/scores", tokenAuth(srv.withStore(srv.dailyScoresHandler)))
+	mux.Handle("POST /api/paths/validate", tokenAuth(srv.withStore(srv.validatePathHandler)))
 
 	// Spotify OAuth begin (public)
 	mux.HandleFunc("/auth/start", auth.HomePage)
@@ -137,7 +202,7 @@ func handleLookup(w http.ResponseWriter, r *http.Request) {
 
 // POST /createPlaylist
 // POST /createPlaylist
-func createPlaylistHandler(w http.ResponseWriter, r *http.Request) {
+func (srv *server) createPlaylistHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	w.Header().Set("Content-Type", "application/json")
 	fmt.Println("HIT CREATE PLAYLIST ENDPOINT")
@@ -202,7 +267,26 @@ func createPlaylistHandler(w http.ResponseWriter, r *http.Request) {
 		req.PlaylistName = req.PlaylistName[:100]
 	}
 
-	// 2. Convert BFS track names → Spotify IDs
+	// 2. Convert BFS tracks → Spotify IDs: MusicBrainz URL links first,
+	// title search only for recordings without one
+	var recIDs []string
+	for _, step := range path {
+		for _, t := range step.Tracks {
+			if t.RecordingID != "" {
+				recIDs = append(recIDs, t.RecordingID)
+			}
+		}
+	}
+	linked := map[string]string{}
+	if srv.store != nil {
+		ids, err := srv.store.SpotifyIDsForMBIDs(ctx, search.CrosswalkRecording, recIDs)
+		if err != nil {
+			log.Printf("crosswalk lookup failed: %v", err)
+		} else {
+			linked = ids
+		}
+	}
+
 	var spotifyIDs []string
 
 	for _, step := range path {
@@ -210,6 +294,11 @@ func createPlaylistHandler(w http.ResponseWriter, r *http.Request) {
 		to := step.To
 
 		for _, t := range step.Tracks {
+			if id, ok := linked[t.RecordingID]; ok {
+				spotifyIDs = append(spotifyIDs, id)
+				continue
+			}
+
 			recName := t.RecordingName
 			if recName == "" {
 				recName = t.Name
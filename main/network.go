@@ -0,0 +1,83 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/search"
+)
+
+// networkNode / networkEdge are the shapes the interactive graph
+// templates consume for an ego-network.
+type networkNode struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Depth int    `json:"depth"`
+}
+
+type networkEdge struct {
+	Source string             `json:"source"`
+	Target string             `json:"target"`
+	Weight int                `json:"weight"`
+	Tracks []search.TrackInfo `json:"tracks,omitempty"`
+}
+
+type networkResponse struct {
+	Center string        `json:"center"`
+	Depth  int           `json:"depth"`
+	Nodes  []networkNode `json:"nodes"`
+	Edges  []networkEdge `json:"edges"`
+}
+
+// Network flattens the graph into nodes plus undirected edges. Edges
+// that were discovered from both ends are reported once.
+func (g *Graph) Network(centerID string, depth int) networkResponse {
+	resp := networkResponse{
+		Center: centerID,
+		Depth:  depth,
+		Nodes:  make([]networkNode, 0, len(g.Nodes)),
+		Edges:  []networkEdge{},
+	}
+
+	for id, a := range g.Nodes {
+		resp.Nodes = append(resp.Nodes, networkNode{
+			ID:    id,
+			Name:  a.Name,
+			Depth: g.Depth[id],
+		})
+	}
+	sort.Slice(resp.Nodes, func(i, j int) bool {
+		if resp.Nodes[i].Depth != resp.Nodes[j].Depth {
+			return resp.Nodes[i].Depth < resp.Nodes[j].Depth
+		}
+		return resp.Nodes[i].Name < resp.Nodes[j].Name
+	})
+
+	seen := make(map[[2]string]bool)
+	for from, tos := range g.Edges {
+		for _, to := range tos {
+			key := [2]string{from, to}
+			if to < from {
+				key = [2]string{to, from}
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			e := networkEdge{
+				Source: from,
+				Target: to,
+				Weight: g.Weights[from][to],
+			}
+			for _, t := range g.TrackEdges[from][to] {
+				e.Tracks = append(e.Tracks, search.TrackInfoFromTrack(t))
+			}
+			resp.Edges = append(resp.Edges, e)
+		}
+	}
+	sort.Slice(resp.Edges, func(i, j int) bool {
+		return resp.Edges[i].Weight > resp.Edges[j].Weight
+	})
+
+	return resp
+}
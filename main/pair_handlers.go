@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+const (
+	hardestPairsDefaultLimit = 20
+	hardestPairsMaxLimit     = 100
+)
+
+// ------------------------------------------------------------
+// GET /api/pairs/hardest?limit=20
+// ------------------------------------------------------------
+// Leaderboard of the top-artist pairs that are farthest apart, from the
+// matrix built by cmd/pairmatrix.
+func (srv *server) hardestPairsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = hardestPairsDefaultLimit
+	}
+	if limit > hardestPairsMaxLimit {
+		limit = hardestPairsMaxLimit
+	}
+
+	s := srv.store
+
+	pairs, err := s.HardestPairs(r.Context(), limit)
+	if err != nil {
+		log.Printf("hardest pairs failed: %v", err)
+		http.Error(w, `{"error":"pair_lookup_failed"}`, http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"pairs": pairs,
+	})
+}
+
+// ------------------------------------------------------------
+// GET /api/pairs/lookup?start=<name>&target=<name>
+// ------------------------------------------------------------
+// Instant distance and witness path for a precomputed pair.
+func (srv *server) pairLookupHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	q := r.URL.Query()
+
+	start, err := srv.store.ResolveArtistOnce(r.Context(), q.Get("start"))
+	if err != nil {
+		http.Error(w, `{"error":"start_not_found"}`, http.StatusNotFound)
+		return
+	}
+	target, err := srv.store.ResolveArtistOnce(r.Context(), q.Get("target"))
+	if err != nil {
+		http.Error(w, `{"error":"target_not_found"}`, http.StatusNotFound)
+		return
+	}
+
+	s := srv.store
+
+	pair, ok, err := s.LookupPair(r.Context(), start.ID, target.ID)
+	if err != nil {
+		log.Printf("pair lookup failed: %v", err)
+		http.Error(w, `{"error":"pair_lookup_failed"}`, http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		http.Error(w, `{"error":"pair_not_precomputed"}`, http.StatusNotFound)
+		return
+	}
+
+	json.NewEncoder(w).Encode(pair)
+}
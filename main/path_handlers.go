@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/Jonnymurillo288/MelodyMap/internal/search"
+)
+
+type validatePathRequest struct {
+	Chain []string `json:"chain"` // artist names or MBIDs, in order
+}
+
+// ------------------------------------------------------------
+// POST /api/paths/validate
+// ------------------------------------------------------------
+// Fact-checks a claimed chain of collaborations: evidence for every
+// valid hop, the first broken link and the shortest bridge that fixes it.
+func (srv *server) validatePathHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req validatePathRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"bad_request"}`, http.StatusBadRequest)
+		return
+	}
+	if len(req.Chain) < 2 {
+		http.Error(w, `{"error":"chain_too_short"}`, http.StatusBadRequest)
+		return
+	}
+	if len(req.Chain) > search.ValidateMaxChain {
+		http.Error(w, `{"error":"chain_too_long"}`, http.StatusBadRequest)
+		return
+	}
+
+	s := srv.store
+
+	res, err := s.ValidatePath(r.Context(), req.Chain)
+	var unknown *search.UnknownArtistError
+	if errors.As(err, &unknown) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"error": "unknown_artist",
+			"index": unknown.Index,
+			"entry": unknown.Entry,
+		})
+		return
+	}
+	if err != nil {
+		log.Printf("path validation failed: %v", err)
+		http.Error(w, `{"error":"validation_failed"}`, http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(res)
+}
@@ -1,54 +1,188 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"log"
 	"net/http"
 
+	"github.com/Jonnymurillo288/MelodyMap/internal/export"
 	"github.com/Jonnymurillo288/MelodyMap/internal/jobs"
 	"github.com/Jonnymurillo288/MelodyMap/internal/search"
+	"github.com/google/uuid"
 )
 
 var GlobalNeighborLookup = make(map[string]frontendStep)
 
 type searchRequest struct {
-	Start  string `json:"start"`
-	Target string `json:"target"`
-	Depth  int    `json:"depth"`
+	Start      string `json:"start"`
+	Target     string `json:"target"`
+	StartMBID  string `json:"start_mbid"`
+	TargetMBID string `json:"target_mbid"`
+	Depth      int    `json:"depth"`
+	Mode       string `json:"mode"`
+	Strategy   string `json:"strategy"`
+}
+
+// ambiguousName lists the comparable matches for one typed name.
+type ambiguousName struct {
+	Query      string                `json:"query"`
+	Candidates []search.ArtistChoice `json:"candidates"`
 }
 
 // ------------------------------------------------------------
 // POST /api/search/start
 // ------------------------------------------------------------
-func startSearchHandler(w http.ResponseWriter, r *http.Request) {
+func (srv *server) startSearchHandler(w http.ResponseWriter, r *http.Request) {
 	var req searchRequest
 	json.NewDecoder(r.Body).Decode(&req)
 
+	switch req.Mode {
+	case "", search.ModeShortest, search.ModeWidest:
+	case search.ModeWeighted:
+		switch req.Strategy {
+		case "", search.StrategyStrength, search.StrategyPopularity:
+		default:
+			http.Error(w, "unknown weight strategy", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "unknown search mode", http.StatusBadRequest)
+		return
+	}
+
+	// An MBID pins its end of the search; the name is only for display.
+	// Merged MBIDs are replaced by the artist they redirect to.
+	for _, end := range []struct {
+		field      string
+		mbid, name *string
+	}{
+		{"start", &req.StartMBID, &req.Start},
+		{"target", &req.TargetMBID, &req.Target},
+	} {
+		if *end.mbid == "" {
+			continue
+		}
+		if _, err := uuid.Parse(*end.mbid); err != nil {
+			http.Error(w, `{"error":"invalid_`+end.field+`_mbid"}`, http.StatusBadRequest)
+			return
+		}
+		a, err := srv.engine.Graph.Resolve(r.Context(), *end.mbid)
+		if err != nil {
+			http.Error(w, `{"error":"`+end.field+`_not_found"}`, http.StatusNotFound)
+			return
+		}
+		*end.mbid = a.ID
+		if *end.name == "" {
+			*end.name = a.Name
+		}
+	}
+
+	// Names matching several artists equally well go back to the client,
+	// which re-submits with the chosen MBID
+	if ambiguous := srv.ambiguousEnds(r.Context(), req); len(ambiguous) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		ambiguous["status"] = "ambiguous"
+		json.NewEncoder(w).Encode(ambiguous)
+		return
+	}
+
+	sreq := search.SearchRequest{
+		Start:      req.Start,
+		Target:     req.Target,
+		StartMBID:  req.StartMBID,
+		TargetMBID: req.TargetMBID,
+		Depth:      req.Depth,
+		Mode:       req.Mode,
+		Strategy:   req.Strategy,
+	}
+
 	// Create job
 	job := jobs.Manager.CreateJob(req.Start, req.Target)
 
+	// Pairs already solved at this data version finish immediately
+	if resp, ok := srv.engine.CachedSearch(r.Context(), sreq); ok {
+		jobs.Manager.Update(job.ID, func(j *jobs.Job) {
+			j.Status = jobs.StatusFinished
+			j.Progress = 1
+			j.Result = *resp
+		})
+		json.NewEncoder(w).Encode(map[string]string{
+			"jobID":  job.ID,
+			"status": string(jobs.StatusFinished),
+		})
+		return
+	}
+
 	// Launch background BFS with the correct request type
-	go search.RunBackgroundBFS(job, search.SearchRequest{
-		Start:  req.Start,
-		Target: req.Target,
-		Depth:  req.Depth,
-	})
+	go srv.engine.RunBackgroundBFS(job, sreq)
 
 	json.NewEncoder(w).Encode(map[string]string{
 		"jobID": job.ID,
 	})
 }
 
+// ambiguousEnds checks each end given only by name and returns the
+// candidate lists of the ambiguous ones, keyed "start"/"target". Lookup
+// failures are logged and treated as unambiguous; the search itself will
+// report them. Nothing is checked unless the database is also the graph
+// source, so candidates are always artists the search can reach.
+func (srv *server) ambiguousEnds(ctx context.Context, req searchRequest) map[string]any {
+	out := map[string]any{}
+	if srv.store == nil || !search.GraphSourceUsesDB() || (req.StartMBID != "" && req.TargetMBID != "") {
+		return out
+	}
+
+	for _, end := range []struct{ field, name, mbid string }{
+		{"start", req.Start, req.StartMBID},
+		{"target", req.Target, req.TargetMBID},
+	} {
+		if end.mbid != "" || end.name == "" {
+			continue
+		}
+		if _, ok := search.ParseSpotifyArtistID(end.name); ok {
+			continue
+		}
+		cands, err := srv.store.AmbiguousArtist(ctx, end.name)
+		if err != nil {
+			log.Printf("ambiguity check failed for %s: %v", end.name, err)
+			continue
+		}
+		if len(cands) > 0 {
+			out[end.field] = ambiguousName{Query: end.name, Candidates: cands}
+		}
+	}
+	return out
+}
+
 // ------------------------------------------------------------
 // GET /api/search/status?id=<jobID>
 // ------------------------------------------------------------
-func searchStatusHandler(w http.ResponseWriter, r *http.Request) {
+// Optional &format=graphml|gexf|dot exports a finished path instead.
+func (srv *server) searchStatusHandler(w http.ResponseWriter, r *http.Request) {
 	id := r.URL.Query().Get("jobID")
 
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+
 	job, ok := jobs.Manager.Get(id)
 	if !ok {
 		http.Error(w, "job not found", http.StatusNotFound)
 		return
 	}
 
+	if format != "" {
+		result, ok := job.Result.(search.SearchResponse)
+		if job.Status != jobs.StatusFinished || !ok {
+			http.Error(w, "job has no path to export", http.StatusConflict)
+			return
+		}
+		writeGraphExport(w, format, "path-"+job.ID, export.FromSearchResponse(result))
+		return
+	}
+
 	json.NewEncoder(w).Encode(job)
 }
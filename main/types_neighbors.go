@@ -4,10 +4,11 @@ import (
 	sixdegrees "github.com/Jonnymurillo288/MelodyMap/sixDegrees"
 )
 
-// Returned by MusicBrainzNeighborProvider()
-// Used only inside RunSearchOptsBFS
+// NeighborEdge is one edge the GraphBuilder keeps, with its weight and
+// optional track evidence.
 type NeighborEdge struct {
 	Artist *sixdegrees.Artists
 	Track  []sixdegrees.Track
 	Link   string // optional: MB recording URI
+	Shared int    // shared recordings; used to rank and prune neighbors
 }
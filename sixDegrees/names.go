@@ -0,0 +1,25 @@
+package sixdegrees
+
+import (
+	"regexp"
+	"strings"
+)
+
+var reArtistSeparators = regexp.MustCompile(`[-_/·•*]`)
+
+// NormalizeArtistName reduces an artist name to a comparison key: the
+// same folding as track names (case, diacritics, punctuation), with "&"
+// and "+" read as "and", hyphens as spaces and a leading "the" dropped.
+// "The Beatles", "beatles" and "Béatles" all normalize to "beatles".
+func NormalizeArtistName(s string) string {
+	s = foldName(s)
+	s = strings.NewReplacer("&", " and ", "+", " and ").Replace(s)
+	s = reArtistSeparators.ReplaceAllString(s, " ")
+	s = reStripPunctuation.ReplaceAllString(s, "")
+
+	toks := strings.Fields(s)
+	if len(toks) > 1 && toks[0] == "the" {
+		toks = toks[1:]
+	}
+	return strings.Join(toks, " ")
+}
@@ -0,0 +1,21 @@
+package sixdegrees
+
+import "testing"
+
+func TestNormalizeArtistName(t *testing.T) {
+	cases := map[string]string{
+		"The Beatles":       "beatles",
+		"Beyoncé":           "beyonce",
+		"Simon & Garfunkel": "simon and garfunkel",
+		"Joey + Rory":       "joey and rory",
+		"Jay-Z":             "jay z",
+		"P. Diddy":          "p diddy",
+		"  Adele ":          "adele",
+		"The The":           "the",
+	}
+	for in, want := range cases {
+		if got := NormalizeArtistName(in); got != want {
+			t.Fatalf("NormalizeArtistName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
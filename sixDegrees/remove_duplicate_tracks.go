@@ -71,9 +71,14 @@ func stripDiacritics(s string) string {
 	return string(out)
 }
 
+// foldName lower-cases s and strips diacritics; it is the first step of
+// every name normalization in this package.
+func foldName(s string) string {
+	return stripDiacritics(strings.ToLower(s))
+}
+
 func normalizeName(s string) string {
-	s = strings.ToLower(s)
-	s = stripDiacritics(s)
+	s = foldName(s)
 
 	replacements := map[string]string{
 		" pt ":           " part ",
@@ -363,5 +368,16 @@ func mergeTracks(a, b Track) Track {
 	if a.PhotoURL == "" && b.PhotoURL != "" {
 		a.PhotoURL = b.PhotoURL
 	}
+	if a.ReleaseTitle == "" && b.ReleaseTitle != "" {
+		a.ReleaseTitle = b.ReleaseTitle
+		a.ReleaseGroupID = b.ReleaseGroupID
+		a.ReleaseType = b.ReleaseType
+	}
+	if a.ReleaseYear == 0 && b.ReleaseYear != 0 {
+		a.ReleaseYear = b.ReleaseYear
+	}
+	if len(a.Credits) == 0 && len(b.Credits) != 0 {
+		a.Credits = b.Credits
+	}
 	return a
 }
@@ -17,6 +17,21 @@ type Track struct {
 	RecordingID   string
 	RecordingName string
 	Featured      []*Artists
+
+	// Release evidence for the hop this track connects
+	ReleaseTitle   string
+	ReleaseGroupID string
+	ReleaseYear    int
+	ReleaseType    string // album, single, ep, compilation, live, ...
+	Credits        []CreditedName
+}
+
+// CreditedName is how an artist was credited on a track, which may differ
+// from the artist's canonical name (e.g. "Puff Daddy" vs "Diddy").
+type CreditedName struct {
+	ArtistID   string
+	ArtistName string
+	CreditedAs string
 }
 
 type trackResponse struct {